@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/ksync/ksync/pkg/cli"
+	"github.com/ksync/ksync/pkg/ksync"
 	"github.com/ksync/ksync/pkg/ksync/cluster"
 )
 
@@ -51,6 +53,22 @@ WARNING: USING THE "NUKE" OPTION WILL REMOVE YOUR CONFIG. USE WITH CAUTION.
 		log.Fatal(err)
 	}
 
+	flags.Bool(
+		"remote-state",
+		false,
+		"Remove folders and devices left on the remote syncthing servers that no longer belong to a spec")
+	if err := c.BindFlag("remote-state"); err != nil {
+		log.Fatal(err)
+	}
+
+	flags.Bool(
+		"dry-run",
+		false,
+		"Show which remote components would be removed, without removing them.")
+	if err := c.BindFlag("dry-run"); err != nil {
+		log.Fatal(err)
+	}
+
 	flags.Bool(
 		"nuke",
 		false,
@@ -62,18 +80,55 @@ WARNING: USING THE "NUKE" OPTION WILL REMOVE YOUR CONFIG. USE WITH CAUTION.
 	return c.Cmd
 }
 
+// cleanRemote removes everything ksync created on the cluster, found by its
+// labels. This includes leftovers from older installs.
 func (c *cleanCmd) cleanRemote() {
+	dryRun := c.Viper.GetBool("dry-run")
+
 	service := cluster.NewService()
 
-	// Check that the daemonset is running remotely
-	if isInstalled, err := service.IsInstalled(); err != nil {
+	// Workloads go back to how they were before the pods ksync runs on them
+	// are gone.
+	sidecars, err := service.RemoveSidecars(dryRun)
+	for _, workload := range sidecars {
+		if dryRun {
+			fmt.Printf("Would remove the %s sidecar from %s\n",
+				cluster.SidecarName, workload)
+		} else {
+			fmt.Printf("Removed the %s sidecar from %s\n",
+				cluster.SidecarName, workload)
+		}
+	}
+
+	if err != nil {
 		log.Fatal(err)
-	} else if !isInstalled {
+	}
+
+	removed, err := service.Remove(dryRun)
+	for _, resource := range removed {
+		if dryRun {
+			fmt.Printf("Would remove %s\n", resource)
+		} else {
+			fmt.Printf("Removed %s\n", resource)
+		}
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(removed) == 0 && len(sidecars) == 0 {
 		log.Infoln("Remote components are not installed")
-		return
+	}
+}
+
+func (c *cleanCmd) cleanRemoteState() {
+	list := ksync.NewSpecList()
+	if err := list.Update(); err != nil {
+		log.Fatal(err)
 	}
 
-	if err := service.Remove(); err != nil {
+	if err := ksync.ReconcileRemote(list); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -104,11 +159,16 @@ func (c *cleanCmd) run(cmd *cobra.Command, args []string) {
 		c.cleanRemote()
 	}
 
+	if c.Viper.GetBool("remote-state") {
+		c.cleanRemoteState()
+	}
+
 	if c.Viper.GetBool("nuke") {
 		c.fromOrbit()
 	}
 
-	if !c.Viper.GetBool("local") && !c.Viper.GetBool("remote") {
+	if !c.Viper.GetBool("local") && !c.Viper.GetBool("remote") &&
+		!c.Viper.GetBool("remote-state") {
 		c.cleanLocal()
 		c.cleanRemote()
 	}
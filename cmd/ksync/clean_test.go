@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ksync/ksync/pkg/ksync/cluster"
+)
+
+func TestCleanRemoteDryRun(t *testing.T) {
+	previous := cluster.Client
+	viper.Set("daemonset-namespace", "kube-system")
+	defer func() {
+		cluster.Client = previous
+		viper.Set("daemonset-namespace", "")
+	}()
+
+	client := fake.NewSimpleClientset(&appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system",
+			Name:      "ksync",
+			Labels:    map[string]string{"name": "ksync", "app": "ksync"},
+		},
+	})
+	cluster.Client = client
+
+	c := &cleanCmd{}
+	cmd := c.new()
+	require.NoError(t, cmd.Flags().Set("remote", "true"))
+	require.NoError(t, cmd.Flags().Set("dry-run", "true"))
+
+	// A dry run still needs the cluster, to find what would be removed.
+	assert.True(t, needsCluster(cmd))
+
+	c.cleanRemote()
+
+	for _, action := range client.Actions() {
+		assert.Equal(t, "list", action.GetVerb())
+	}
+
+	_, err := client.AppsV1().DaemonSets("kube-system").Get(
+		"ksync", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"strings"
 	"time"
 
 	petname "github.com/dustinkirkland/golang-petname"
@@ -23,7 +24,8 @@ type createCmd struct {
 func (cmd *createCmd) new() *cobra.Command {
 	long := `Create a new spec to sync files between a local and remote directory
   for specific containers running on the cluster.`
-	example := `ksync create --local-read-only /code /go/src/github.com/ksync/code`
+	example := `ksync create --local-read-only /code /go/src/github.com/ksync/code
+  ksync create --sync-transport=exec --selector=app=app $(pwd)/code /code`
 
 	cmd.Init("ksync", &cobra.Command{
 		Use:     "create [flags] [local path] [remote path]",
@@ -82,6 +84,18 @@ func (cmd *createCmd) new() *cobra.Command {
 		log.Fatal(err)
 	}
 
+	flags.String(
+		"sync-transport",
+		ksync.SpecTransportSyncthing,
+		fmt.Sprintf(
+			"How files are moved into the container, one of: %s. sidecar runs "+
+				"syncthing in the pod, the remote path must be on a volume. exec "+
+				"does not need the ksync DaemonSet and only syncs from local to remote.",
+			strings.Join(ksync.SpecTransports, ", ")))
+	if err := cmd.BindFlag("sync-transport"); err != nil {
+		log.Fatal(err)
+	}
+
 	return cmd.Cmd
 }
 
@@ -119,6 +133,8 @@ func (cmd *createCmd) run(_ *cobra.Command, args []string) {
 		RemotePath: syncPath.Remote,
 
 		Reload: cmd.Viper.GetBool("reload"),
+
+		Transport: cmd.Viper.GetString("sync-transport"),
 	}
 
 	if err := newSpec.IsValid(); err != nil {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/ksync/ksync/pkg/cli"
 	"github.com/ksync/ksync/pkg/ksync"
+	"github.com/ksync/ksync/pkg/ksync/cluster"
 	pb "github.com/ksync/ksync/pkg/proto"
 )
 
@@ -79,6 +80,11 @@ func (d *deleteCmd) delete(name string) {
 		log.Fatalf("%s does not exist. Did you mean something else?", name)
 	}
 
+	spec, err := specs.Get(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	log.Debugf("deleting spec %s", name)
 	if err := specs.Delete(name); err != nil {
 		log.Fatalf("Could not delete %s: %v", name, err)
@@ -87,6 +93,14 @@ func (d *deleteCmd) delete(name string) {
 	if err := specs.Save(); err != nil {
 		log.Fatal(err)
 	}
+
+	// Watch removes the sidecar as well when it is running, it might not be.
+	if spec.Details.SyncTransport() == ksync.SpecTransportSidecar {
+		if err := cluster.RemoveSidecar(
+			spec.Details.Namespace, spec.Details.Name); err != nil {
+			log.Warnf("unable to remove the sidecar for %s: %v", name, err)
+		}
+	}
 }
 
 func (d *deleteCmd) deleteAll() {
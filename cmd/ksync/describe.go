@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/ksync/ksync/pkg/cli"
+	"github.com/ksync/ksync/pkg/ksync"
+	pb "github.com/ksync/ksync/pkg/proto"
+)
+
+type describeCmd struct {
+	cli.BaseCmd
+}
+
+func (d *describeCmd) new() *cobra.Command {
+	long := `Describe specs in detail.
+
+	Shows the configuration of each spec as well as every service syncing for it,
+	including the full reason the service last failed to start.`
+	example := `ksync describe wasp`
+
+	d.Init("ksync", &cobra.Command{
+		Use:     "describe [name]...",
+		Short:   "Describe specs in detail.",
+		Long:    long,
+		Example: example,
+		Args:    cobra.MinimumNArgs(1),
+		Run:     d.run,
+	})
+
+	return d.Cmd
+}
+
+func (d *describeCmd) out(name string, spec *pb.Spec) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush() // nolint: errcheck
+
+	details := spec.Details
+
+	fmt.Fprintf(w, "Name:\t%s\n", name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", details.Namespace)
+	fmt.Fprintf(w, "Selector:\t%s\n", strings.Join(details.Selector, ","))
+	fmt.Fprintf(w, "Container:\t%s\n", details.ContainerName)
+	fmt.Fprintf(w, "Local:\t%s\n", details.LocalPath)
+	fmt.Fprintf(w, "Remote:\t%s\n", details.RemotePath)
+	fmt.Fprintf(w, "Reload:\t%t\n", details.Reload)
+	fmt.Fprintf(w, "Transport:\t%s\n", transport(details))
+	fmt.Fprintf(w, "Status:\t%s\n", spec.Status)
+	fmt.Fprintf(w, "Services:\t%d\n", len(spec.Services.Items))
+
+	for _, service := range spec.Services.Items {
+		cntr := service.RemoteContainer
+
+		fmt.Fprintf(w, "\n  Pod:\t%s\n", cntr.PodName)
+		fmt.Fprintf(w, "  Node:\t%s\n", cntr.NodeName)
+		fmt.Fprintf(w, "  Container:\t%s (%s)\n", cntr.ContainerName, cntr.Id)
+		fmt.Fprintf(w, "  Status:\t%s\n", service.Status)
+		fmt.Fprintf(w, "  Attempts:\t%d\n", service.Attempts)
+
+		if service.StartupMs > 0 {
+			fmt.Fprintf(w, "  Startup:\t%s\n",
+				time.Duration(service.StartupMs)*time.Millisecond)
+		}
+
+		if service.LastError == "" {
+			continue
+		}
+
+		fmt.Fprintf(w, "  Last Error:\t%s\n", service.LastError)
+		fmt.Fprintf(w, "  Last Error Time:\t%s\n",
+			time.Unix(service.LastErrorTime, 0).Format(time.RFC3339))
+	}
+}
+
+func (d *describeCmd) run(cmd *cobra.Command, args []string) {
+	withTimeout, _ := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+
+	conn, err := grpc.DialContext(
+		withTimeout,
+		fmt.Sprintf("127.0.0.1:%d", viper.GetInt("port")),
+		[]grpc.DialOption{
+			grpc.WithBlock(),
+			grpc.WithInsecure(),
+		}...)
+	if err != nil {
+		log.Debug(err)
+		log.Fatal("Having problems querying status. Are you running watch?")
+	}
+	defer conn.Close() // nolint: errcheck
+
+	client := pb.NewKsyncClient(conn)
+
+	resp, err := client.GetSpecList(context.Background(), &empty.Empty{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sort.Strings(args)
+	for i, name := range args {
+		spec, ok := resp.Items[name]
+		if !ok {
+			log.Fatalf("%s does not exist. Did you mean something else?", name)
+		}
+
+		if i > 0 {
+			fmt.Println()
+		}
+
+		d.out(name, spec)
+	}
+}
+
+// transport is how files are moved for a spec, older specs do not have one
+// set and use syncthing.
+func transport(details *pb.SpecDetails) string {
+	if details.Transport == "" {
+		return ksync.SpecTransportSyncthing
+	}
+
+	return details.Transport
+}
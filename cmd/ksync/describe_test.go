@@ -0,0 +1,17 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDescribeNew(t *testing.T) {
+	testCobra := &describeCmd{}
+	cmd := testCobra.new()
+
+	assert.IsTypef(t, reflect.TypeOf(&cobra.Command{}), reflect.TypeOf(cmd), "New command is of type %s", reflect.TypeOf(cmd))
+}
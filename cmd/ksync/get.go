@@ -50,6 +50,14 @@ func (g *getCmd) new() *cobra.Command {
 		log.Fatal(err)
 	}
 
+	flags.Bool(
+		"wide",
+		false,
+		"also show the connection to each node and the health of its tunnels")
+	if err := g.BindFlag("wide"); err != nil {
+		log.Fatal(err)
+	}
+
 	return g.Cmd
 }
 
@@ -81,8 +89,10 @@ func (g *getCmd) out(specs *pb.SpecList) { // nolint: gocyclo
 		}
 		spec := specs.Items[name]
 
+		// Services show their own status, unless the connection to the cluster
+		// has been lost.
 		status := spec.Status
-		if len(spec.Services.Items) > 0 {
+		if len(spec.Services.Items) > 0 && status != "disconnected" {
 			status = ""
 		}
 
@@ -116,11 +126,16 @@ func (g *getCmd) out(specs *pb.SpecList) { // nolint: gocyclo
 		})
 
 		for _, service := range spec.Services.Items {
+			status := service.Status
+			if status == "error" && service.LastError != "" {
+				status = fmt.Sprintf("error: %s", shortReason(service.LastError))
+			}
+
 			table.Append([]string{
 				"",
 				"",
 				"",
-				service.Status,
+				status,
 				service.RemoteContainer.PodName,
 				spec.Details.ContainerName,
 			})
@@ -130,6 +145,19 @@ func (g *getCmd) out(specs *pb.SpecList) { // nolint: gocyclo
 	table.Render()
 }
 
+// maxReasonLength is how much of an error is shown in the table, `describe`
+// has the full one.
+var maxReasonLength = 40
+
+func shortReason(reason string) string {
+	reason = strings.SplitN(reason, "\n", 2)[0]
+	if len(reason) > maxReasonLength {
+		reason = reason[:maxReasonLength-3] + "..."
+	}
+
+	return reason
+}
+
 func (g *getCmd) run(cmd *cobra.Command, args []string) {
 	// This is connecting locally and it is very unlikely watch is overloaded,
 	// set the timeout *super* short to make it easier on the users when they
@@ -173,5 +201,72 @@ func (g *getCmd) run(cmd *cobra.Command, args []string) {
 		fmt.Printf("\n")
 	} else {
 		g.out(resp)
+
+		if g.Viper.GetBool("wide") {
+			g.connections(client)
+		}
+
+		g.restarts(client)
+	}
+}
+
+// Show every node being synced with and whether its tunnels are up. Folders
+// on the same node share these.
+func (g *getCmd) connections(client pb.KsyncClient) {
+	conns, err := client.GetConnections(context.Background(), &empty.Empty{})
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	fmt.Println()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator(" ")
+	table.SetHeader([]string{"Node", "Folders", "Tunnel", "Local", "Remote", "Health"})
+
+	for _, conn := range conns.Items {
+		table.Append([]string{
+			conn.NodeName,
+			fmt.Sprintf("%d", conn.Refs),
+		})
+
+		for _, tun := range conn.Tunnels {
+			health := "unhealthy"
+			if tun.Healthy {
+				health = "healthy"
+			}
+
+			table.Append([]string{
+				"",
+				"",
+				tun.Name,
+				fmt.Sprintf("%d", tun.LocalPort),
+				fmt.Sprintf("%d", tun.RemotePort),
+				health,
+			})
+		}
 	}
+
+	table.Render()
+}
+
+// Let users know when the local syncthing has been crashing, as that would
+// interrupt syncing.
+func (g *getCmd) restarts(client pb.KsyncClient) {
+	alive, err := client.IsAlive(context.Background(), &empty.Empty{})
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	if alive.Restarts == 0 {
+		return
+	}
+
+	fmt.Printf(
+		"\nsyncthing has been restarted %d time(s), last exit: %s\n",
+		alive.Restarts,
+		alive.LastExit)
 }
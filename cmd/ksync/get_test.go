@@ -18,3 +18,12 @@ func TestGetNew(t *testing.T) {
 	assert.IsTypef(t, reflect.TypeOf(&cobra.Command{}), reflect.TypeOf(cmd), "New command is of type %s", reflect.TypeOf(cmd))
 	// TODO: Write more specific test cases
 }
+
+func TestShortReason(t *testing.T) {
+	assert.Equal(t, "connection refused", shortReason("connection refused"))
+	assert.Equal(t, "first line", shortReason("first line\nsecond line"))
+
+	long := shortReason("rpc error: code = Unavailable desc = all SubConns are in TransientFailure")
+	assert.Len(t, long, maxReasonLength)
+	assert.Equal(t, "rpc error: code = Unavailable desc = ...", long)
+}
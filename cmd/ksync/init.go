@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -31,7 +32,8 @@ func (i *initCmd) new() *cobra.Command {
 	long := `Prepare ksync.
 
 	Both the local host and remote cluster are initialized.`
-	example := `ksync init --local`
+	example := `ksync init --local
+  ksync init --dry-run -o yaml > ksync.yaml`
 
 	i.Init("ksync", &cobra.Command{
 		Use:     "init [flags]",
@@ -52,6 +54,14 @@ func (i *initCmd) new() *cobra.Command {
 		log.Fatal(err)
 	}
 
+	flags.Bool(
+		"force",
+		false,
+		"Upgrade even when the cluster already runs this version.")
+	if err := i.BindFlag("force"); err != nil {
+		log.Fatal(err)
+	}
+
 	flags.Bool(
 		"local",
 		true,
@@ -83,11 +93,31 @@ func (i *initCmd) new() *cobra.Command {
 		"psp",
 		"p",
 		true,
-		"create/upgrade psp.")
+		"Create/upgrade a PodSecurityPolicy. Clusters that do not support them use Pod Security Admission instead. With --dry-run, only when set explicitly.")
 	if err := i.BindFlag("psp"); err != nil {
 		log.Fatal(err)
 	}
 
+	flags.Bool(
+		"dry-run",
+		false,
+		"Print what would be added to the cluster instead of adding it.")
+	if err := i.BindFlag("dry-run"); err != nil {
+		log.Fatal(err)
+	}
+
+	flags.StringP(
+		"output",
+		"o",
+		cluster.ManifestYAML,
+		fmt.Sprintf(
+			"Format to print with --dry-run, one of: %s, %s.",
+			cluster.ManifestYAML,
+			cluster.ManifestJSON))
+	if err := i.BindFlag("output"); err != nil {
+		log.Fatal(err)
+	}
+
 	return i.Cmd
 }
 
@@ -139,6 +169,53 @@ func (i *initCmd) remotePostChecks() {
 	fmt.Println()
 }
 
+// upgradeRemote upgrades the cluster service when it is not running this
+// version, waits for the rollout on every node and puts the previous version
+// back when it fails.
+func (i *initCmd) upgradeRemote() {
+	var upgrade *cluster.Upgrade
+	if err := cli.TaskOut("Checking the cluster version", func() error {
+		var err error
+		upgrade, err = cluster.NewService().NewUpgrade(ksync.GitTag)
+		return err
+	}); err != nil {
+		log.Fatal()
+	}
+
+	if !upgrade.Required() && !i.Viper.GetBool("force") {
+		fmt.Printf("Already running %s, use --force to upgrade anyway.\n",
+			upgrade.ToVersion)
+		return
+	}
+
+	if err := cli.TaskOut("Upgrading ksync on the cluster", func() error {
+		return upgrade.Apply(i.Viper.GetBool("psp"))
+	}); err != nil {
+		log.Fatal()
+	}
+
+	rolloutErr := cli.TaskOut("Waiting for the rollout", upgrade.Wait)
+	i.printNodes(upgrade)
+
+	if rolloutErr != nil {
+		if err := cli.TaskOut("Rolling back", upgrade.Rollback); err != nil {
+			log.Error(err)
+		}
+		i.printNodes(upgrade)
+
+		fmt.Printf("\nChanges that were rolled back:\n%s\n", upgrade.Summary())
+		log.Fatal()
+	}
+
+	fmt.Printf("\n%s\n", upgrade.Summary())
+}
+
+func (i *initCmd) printNodes(upgrade *cluster.Upgrade) {
+	for _, node := range upgrade.Nodes {
+		fmt.Printf("    %-36s    %s\n", node.NodeName, node)
+	}
+}
+
 func (i *initCmd) initRemote() {
 	if !i.Viper.GetBool("skip-checks") {
 		i.remotePreChecks()
@@ -146,12 +223,16 @@ func (i *initCmd) initRemote() {
 
 	fmt.Println("==== Cluster Environment ====")
 
-	add := func() error {
-		return cluster.NewService().Run(i.Viper.GetBool("upgrade"), i.Viper.GetBool("psp"))
-	}
+	if i.Viper.GetBool("upgrade") {
+		i.upgradeRemote()
+	} else {
+		add := func() error {
+			return cluster.NewService().Run(false, i.Viper.GetBool("psp"))
+		}
 
-	if err := cli.TaskOut("Adding ksync to the cluster", add); err != nil {
-		log.Fatal()
+		if err := cli.TaskOut("Adding ksync to the cluster", add); err != nil {
+			log.Fatal()
+		}
 	}
 
 	if err := cli.TaskOut(
@@ -183,6 +264,20 @@ func (i *initCmd) initLocal() {
 
 // TODO: need a better error with instructions on how to fix errors starting radar
 func (i *initCmd) run(cmd *cobra.Command, args []string) {
+	// Only the manifests are printed, so that they can be applied some other
+	// way. Nothing local or remote is touched. Without the cluster to ask,
+	// PodSecurityPolicy is only rendered when asked for explicitly, newer
+	// clusters do not serve it.
+	if i.Viper.GetBool("dry-run") {
+		if err := cluster.NewService().Render(
+			os.Stdout,
+			i.Viper.GetBool("psp") && i.Cmd.Flags().Changed("psp"),
+			i.Viper.GetString("output")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if i.Viper.GetBool("local") {
 		i.initLocal()
 	}
@@ -31,6 +31,7 @@ func main() {
 		(&cleanCmd{}).new(),
 		(&createCmd{}).new(),
 		(&deleteCmd{}).new(),
+		(&describeCmd{}).new(),
 		(&doctorCmd{}).new(),
 		(&getCmd{}).new(),
 		(&initCmd{}).new(),
@@ -159,6 +160,108 @@ func remoteFlags(flags *pflag.FlagSet) { //nolint: gocyclo
 		log.Fatal(err)
 	}
 
+	flags.String(
+		"containerd-root",
+		"/run/containerd",
+		"state directory of containerd, where container filesystems are mounted")
+	if err := flags.MarkHidden("containerd-root"); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("containerd-root"), "ksync"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"containerd-socket",
+		"/run/containerd/containerd.sock",
+		"path to the containerd socket")
+	if err := flags.MarkHidden("containerd-socket"); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("containerd-socket"), "ksync"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"crio-root",
+		"/var/lib/containers/storage",
+		"root directory of the CRI-O storage driver")
+	if err := flags.MarkHidden("crio-root"); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("crio-root"), "ksync"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"crio-socket",
+		"/var/run/crio/crio.sock",
+		"path to the CRI-O socket")
+	if err := flags.MarkHidden("crio-socket"); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("crio-socket"), "ksync"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"transport",
+		cluster.TransportPortForward,
+		fmt.Sprintf(
+			"how to reach the cluster service on each node (%s). The direct "+
+				"transports expose radar and syncthing on the nodes, only use them "+
+				"on trusted networks",
+			strings.Join(cluster.Transports, ", ")))
+
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("transport"), "ksync"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"scope",
+		cluster.ScopeCluster,
+		fmt.Sprintf(
+			"how much of the cluster ksync is installed into (%s). With %s, "+
+				"the cluster service runs in --namespace and only needs permissions "+
+				"there",
+			strings.Join(cluster.Scopes, ", "),
+			cluster.ScopeNamespace))
+
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("scope"), "ksync"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"placement",
+		cluster.PlacementDaemonSet,
+		fmt.Sprintf(
+			"where the cluster service runs (%s). With %s, watch only starts it "+
+				"on the nodes that run pods of a spec",
+			strings.Join(cluster.Placements, ", "),
+			cluster.PlacementOnDemand))
+
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("placement"), "ksync"); err != nil {
+
+		log.Fatal(err)
+	}
+
 	flags.String(
 		"daemonset-namespace",
 		"kube-system",
@@ -193,17 +296,44 @@ func init() {
 func initPersistent(cmd *cobra.Command, args []string) {
 	cli.InitLogging()
 
-	// This is a super special case where we don't want to initialize the k8s
-	// client, instead waiting to test it as part of the doctor process.
-	if !strings.HasPrefix(cmd.Use, "doctor") {
+	if needsCluster(cmd) {
 		initKubeClient()
 	}
 
 	cluster.SetImage(viper.GetString("image"))
 
+	if _, err := cluster.Transport(); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := cluster.Scope(); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := cluster.Placement(); err != nil {
+		log.Fatal(err)
+	}
+
 	cluster.SetErrorHandlers()
 }
 
+// needsCluster is whether the k8s client has to be initialized for cmd. This
+// is a super special case for doctor, which tests the client as part of its
+// checks instead. A dry run of init only renders the manifests, other dry
+// runs (eg. clean) still look around the cluster.
+func needsCluster(cmd *cobra.Command) bool {
+	if strings.HasPrefix(cmd.Use, "doctor") {
+		return false
+	}
+
+	return cmd.Name() != "init" || !isDryRun(cmd)
+}
+
+func isDryRun(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup("dry-run")
+	return flag != nil && flag.Value.String() == "true"
+}
+
 func initKubeClient() {
 	// The act of testing for a config, initializes the config.
 	if err := doctor.IsClusterConfigValid(); err != nil {
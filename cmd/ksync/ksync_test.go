@@ -2,15 +2,28 @@ package main
 
 import (
 	"testing"
-	// "reflect"
-	//
-	// "github.com/stretchr/testify/assert"
-	// "github.com/stretchr/testify/require"
-	//
-	// "github.com/spf13/cobra"
-	// "github.com/spf13/viper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKsync(t *testing.T) {
 	// TODO: There are no exported functions in this file. Write other tests?
 }
+
+func TestNeedsCluster(t *testing.T) {
+	initCommand := (&initCmd{}).new()
+	cleanCommand := (&cleanCmd{}).new()
+	doctorCommand := (&doctorCmd{}).new()
+
+	assert.True(t, needsCluster(initCommand))
+	assert.True(t, needsCluster(cleanCommand))
+	assert.False(t, needsCluster(doctorCommand))
+
+	// Only init renders everything without the cluster.
+	require.NoError(t, initCommand.Flags().Set("dry-run", "true"))
+	require.NoError(t, cleanCommand.Flags().Set("dry-run", "true"))
+
+	assert.False(t, needsCluster(initCommand))
+	assert.True(t, needsCluster(cleanCommand))
+}
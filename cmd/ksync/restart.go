@@ -123,6 +123,7 @@ func (r *reloadCmd) reload(specName string) {
 
 	_, err = client.Restart(context.Background(), &pb.ContainerPath{
 		ContainerId: containerID,
+		Runtime:     service.RemoteContainer.Runtime,
 	})
 
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ksync/ksync/pkg/cli"
 	"github.com/ksync/ksync/pkg/ksync"
+	"github.com/ksync/ksync/pkg/ksync/cluster"
 	"github.com/ksync/ksync/pkg/ksync/server"
 )
 
@@ -49,6 +50,14 @@ func (w *watchCmd) new() *cobra.Command {
 		log.Fatal(err)
 	}
 
+	flags.Int(
+		"max-parallel-startup",
+		ksync.DefaultMaxParallelStartup,
+		"how many folders can be starting at the same time")
+	if err := w.BindFlag("max-parallel-startup"); err != nil {
+		log.Fatal(err)
+	}
+
 	return w.Cmd
 }
 
@@ -81,7 +90,10 @@ func (w *watchCmd) local(list *ksync.SpecList) {
 func (w *watchCmd) run(cmd *cobra.Command, args []string) {
 	list := ksync.NewSpecList()
 
-	w.local(list)
+	// Catch configuration errors before going into the background.
+	if err := list.Update(); err != nil {
+		log.Fatal(err)
+	}
 
 	if w.Viper.GetBool("daemon") {
 		context := getDaemonContext()
@@ -98,12 +110,43 @@ func (w *watchCmd) run(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	if err := ksync.NewSyncthing().Run(); err != nil {
+	ksync.SetMaxParallelStartup(w.Viper.GetInt("max-parallel-startup"))
+
+	local := ksync.NewSyncthing()
+	if err := local.Run(); err != nil {
 		log.Fatal(err)
 	}
 
+	go func() {
+		log.Fatal(<-local.Failed())
+	}()
+
+	// Syncthing's state is kept between runs, anything that isn't part of a spec
+	// anymore is removed before the specs start syncing. The cluster might not
+	// be reachable yet, failing to clean up shouldn't stop anything from
+	// syncing once it is.
+	if err := local.CleanState(list); err != nil {
+		log.Warn(err)
+	}
+
+	// The same goes for the remote servers.
+	if err := ksync.ReconcileRemote(list); err != nil {
+		log.Warn(err)
+	}
+
+	// Agents started on demand are removed once their node is not synced to
+	// any more.
+	if cluster.OnDemand() {
+		go cluster.NewService().CollectAgents()
+	}
+
+	// Folders and devices might not have been saved when syncthing went down.
+	local.OnRestart(list.Reapply)
+
+	w.local(list)
+
 	if err := server.Listen(
-		list, w.Viper.GetString("bind"), viper.GetInt("port")); err != nil {
+		list, local, w.Viper.GetString("bind"), viper.GetInt("port")); err != nil {
 		if strings.Contains(err.Error(), "address already in use") {
 			log.Fatal("It appears that watch is already running. Stop it first.")
 		}
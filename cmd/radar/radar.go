@@ -54,4 +54,44 @@ func init() {
 
 		log.Fatal(err)
 	}
+
+	flags.String(
+		"docker-root",
+		"/var/lib/docker",
+		"Root directory of docker.")
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("docker-root"), "radar"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"containerd-socket",
+		"/run/containerd/containerd.sock",
+		"Path to the containerd socket.")
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("containerd-socket"), "radar"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"containerd-root",
+		"/run/containerd",
+		"State directory of containerd, where container filesystems are mounted.")
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("containerd-root"), "radar"); err != nil {
+
+		log.Fatal(err)
+	}
+
+	flags.String(
+		"crio-socket",
+		"/var/run/crio/crio.sock",
+		"Path to the CRI-O socket.")
+	if err := cli.BindFlag(
+		viper.GetViper(), flags.Lookup("crio-socket"), "radar"); err != nil {
+
+		log.Fatal(err)
+	}
 }
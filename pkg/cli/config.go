@@ -2,12 +2,15 @@ package cli
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	homedir "github.com/mitchellh/go-homedir"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
 )
 
 // InitConfig constructs the configuration from a local configuration file
@@ -55,3 +58,39 @@ func InitConfig(name string) error {
 func ConfigPath() string {
 	return filepath.Dir(viper.ConfigFileUsed())
 }
+
+// ConfigSection reads a top level section of the config file the way it was
+// written. Viper lower cases every key, sections with keys that are not
+// ksync's own (eg. labels) need them as they are. It is nil without a config
+// file or section.
+func ConfigSection(name string) (interface{}, error) {
+	return ReadConfigSection(viper.ConfigFileUsed(), name)
+}
+
+// ReadConfigSection is ConfigSection for the config file at cfgPath.
+func ReadConfigSection(cfgPath, name string) (interface{}, error) {
+	if cfgPath == "" {
+		return nil, nil
+	}
+
+	buf, err := ioutil.ReadFile(cfgPath) // #nosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal(buf, &cfg); err != nil {
+		return nil, err
+	}
+
+	for key, value := range cfg {
+		if strings.EqualFold(key, name) {
+			return value, nil
+		}
+	}
+
+	return nil, nil
+}
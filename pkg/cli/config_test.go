@@ -1,8 +1,12 @@
 package cli
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,3 +16,31 @@ func TestInitConfig(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+func TestConfigSection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ksync")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	cfgPath := filepath.Join(dir, "ksync.yaml")
+	require.NoError(t, ioutil.WriteFile(cfgPath, []byte(`
+remote:
+  labels:
+    Team: platform
+`), 0644))
+
+	remote, err := ReadConfigSection(cfgPath, "remote")
+	require.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{
+		"labels": map[interface{}]interface{}{"Team": "platform"},
+	}, remote)
+
+	missing, err := ReadConfigSection(cfgPath, "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// Without a config file, there is nothing to read.
+	none, err := ReadConfigSection("", "remote")
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+}
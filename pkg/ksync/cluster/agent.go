@@ -0,0 +1,279 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/ksync/ksync/pkg/debug"
+)
+
+// Where the ksync pods run. By default, the DaemonSet runs one on every node.
+// On demand, watch starts an agent pod on the nodes that host pods of a spec
+// and removes it again once nothing on the node is synced any more.
+const (
+	PlacementDaemonSet = "daemonset"
+	PlacementOnDemand  = "on-demand"
+)
+
+const (
+	// agentLabel marks the pods that have been started on demand.
+	agentLabel = "ksync.github.io/agent"
+
+	// agentUsedAnnotation is when an agent was last used by any watch. Every
+	// watch using an agent refreshes it, so that the agents other developers
+	// use are not removed.
+	agentUsedAnnotation = "ksync.github.io/agent-used"
+)
+
+var (
+	// Placements are the supported ways to run the ksync pods.
+	Placements = []string{
+		PlacementDaemonSet,
+		PlacementOnDemand,
+	}
+
+	// agentGracePeriod is how long an agent is kept around after its node has
+	// last been used. Pods being replaced (eg. a deployment rolling) do not
+	// result in the agent being replaced as well. It has to be longer than
+	// agentCollectInterval, that is how often the agents in use are marked.
+	agentGracePeriod = 5 * time.Minute
+
+	// agentCollectInterval is how often agents in use are marked and unused
+	// agents are looked for.
+	agentCollectInterval = time.Minute
+
+	// agentReadyTimeout is how long to wait for an agent to start. It might be
+	// the first time the image is pulled on the node.
+	agentReadyTimeout = 2 * time.Minute
+)
+
+// Placement returns the configured placement, the default is daemonset.
+func Placement() (string, error) {
+	placement := viper.GetString("placement")
+	if placement == "" {
+		return PlacementDaemonSet, nil
+	}
+
+	for _, valid := range Placements {
+		if placement == valid {
+			return placement, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"unsupported placement %s, must be one of: %s",
+		placement,
+		strings.Join(Placements, ", "))
+}
+
+// OnDemand is true when ksync pods are started on demand instead of by the
+// DaemonSet.
+func OnDemand() bool {
+	placement, _ := Placement()
+	return placement == PlacementOnDemand
+}
+
+func (s *Service) agentSelector() string {
+	return labels.SelectorFromSet(
+		merge(map[string]string{agentLabel: "true"}, s.labels)).String()
+}
+
+// agentPod is the DaemonSet's pod, pinned to a single node with node
+// affinity.
+func (s *Service) agentPod(nodeName string) (*v1.Pod, error) {
+	daemonSet, err := s.daemonSet()
+	if err != nil {
+		return nil, err
+	}
+
+	template := daemonSet.Spec.Template
+	delete(template.Annotations, "forceUpdate")
+
+	affinity := &v1.Affinity{}
+	if template.Spec.Affinity != nil {
+		*affinity = *template.Spec.Affinity
+	}
+	affinity.NodeAffinity = &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchFields: []v1.NodeSelectorRequirement{{
+					Key:      "metadata.name",
+					Operator: v1.NodeSelectorOpIn,
+					Values:   []string{nodeName},
+				}},
+			}},
+		},
+	}
+
+	pod := &v1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      s.agentName(nodeName),
+			Labels: merge(
+				map[string]string{agentLabel: "true"}, template.Labels),
+			Annotations: merge(template.Annotations, map[string]string{
+				agentUsedAnnotation: time.Now().UTC().Format(time.RFC3339),
+			}),
+		},
+		Spec: template.Spec,
+	}
+	pod.Spec.Affinity = affinity
+
+	return pod, nil
+}
+
+// agentName is the same for every watch that starts an agent on a node. Only
+// one of them gets to create it. Pending agents have no node yet, looking them
+// up by node would miss them.
+func (s *Service) agentName(nodeName string) string {
+	return fmt.Sprintf("%s-agent-%s", s.name, nodeName)
+}
+
+// EnsureAgent starts the agent on a node, unless it is already there. Agents
+// that have stopped for good are replaced.
+func (s *Service) EnsureAgent(nodeName string) error {
+	podName := s.agentName(nodeName)
+
+	existing, err := Client.CoreV1().Pods(s.Namespace).Get(
+		podName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return err
+	case existing.Status.Phase == v1.PodFailed,
+		existing.Status.Phase == v1.PodSucceeded:
+		// The replacement has the same name, the old one has to be gone
+		// right away. There is nothing running to wait for.
+		gracePeriod := int64(0)
+		if err := Client.CoreV1().Pods(s.Namespace).Delete(
+			podName, &metav1.DeleteOptions{
+				GracePeriodSeconds: &gracePeriod,
+			}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	default:
+		// Another watch might have been about to remove it.
+		return s.markAgentUsed(podName)
+	}
+
+	pod, err := s.agentPod(nodeName)
+	if err != nil {
+		return err
+	}
+
+	fields := debug.MergeFields(s.Fields(), log.Fields{
+		"nodeName": nodeName,
+		"podName":  podName,
+	})
+
+	if _, err := Client.CoreV1().Pods(s.Namespace).Create(pod); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+
+		log.WithFields(fields).Debug("agent started by another watch")
+		return nil
+	}
+
+	log.WithFields(fields).Debug("started agent")
+
+	return nil
+}
+
+func (s *Service) removeAgent(podName string) error {
+	err := Client.CoreV1().Pods(s.Namespace).Delete(
+		podName, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// markAgentUsed records on the cluster that an agent is being used right now.
+func (s *Service) markAgentUsed(podName string) error {
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`,
+		agentUsedAnnotation, time.Now().UTC().Format(time.RFC3339))
+
+	_, err := Client.CoreV1().Pods(s.Namespace).Patch(
+		podName, types.MergePatchType, []byte(patch))
+	return err
+}
+
+// agentLastUsed is when any watch last used an agent. Agents without the
+// annotation count from when they were created.
+func agentLastUsed(pod v1.Pod) time.Time {
+	used, err := time.Parse(time.RFC3339, pod.Annotations[agentUsedAnnotation])
+	if err != nil {
+		return pod.CreationTimestamp.Time
+	}
+
+	return used
+}
+
+// collectAgents marks the agents on nodes this process uses and removes the
+// agents that no watch has used for agentGracePeriod. Agents are not removed
+// right after starting, the connection they are for might not be in the pool
+// yet.
+func (s *Service) collectAgents() error {
+	pods, err := Client.CoreV1().Pods(s.Namespace).List(metav1.ListOptions{
+		LabelSelector: s.agentSelector(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		fields := debug.MergeFields(s.Fields(), log.Fields{
+			"nodeName": pod.Spec.NodeName,
+			"podName":  pod.Name,
+		})
+
+		if nodeInUse(pod.Spec.NodeName, agentGracePeriod) {
+			if err := s.markAgentUsed(pod.Name); err != nil &&
+				!errors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+
+		if time.Since(agentLastUsed(pod)) < agentGracePeriod {
+			continue
+		}
+
+		if err := s.removeAgent(pod.Name); err != nil {
+			return err
+		}
+
+		log.WithFields(fields).Debug("removed unused agent")
+	}
+
+	return nil
+}
+
+// CollectAgents marks the agents in use and removes unused ones periodically,
+// it does not return. The agents of a watch that did not get to stop are
+// removed by the other watches as well.
+func (s *Service) CollectAgents() {
+	ticker := time.NewTicker(agentCollectInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.collectAgents(); err != nil {
+			log.WithFields(s.Fields()).Warnf("unable to remove agents: %v", err)
+		}
+	}
+}
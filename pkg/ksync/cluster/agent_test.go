@@ -0,0 +1,157 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPlacement(t *testing.T) {
+	defer viper.Set("placement", "")
+
+	placement, err := Placement()
+	assert.NoError(t, err)
+	assert.Equal(t, PlacementDaemonSet, placement)
+	assert.False(t, OnDemand())
+
+	viper.Set("placement", PlacementOnDemand)
+	assert.True(t, OnDemand())
+
+	viper.Set("placement", "everywhere")
+	_, err = Placement()
+	assert.Error(t, err)
+}
+
+func TestAgentPod(t *testing.T) {
+	defer viper.Set("placement", "")
+
+	service := NewService()
+
+	pod, err := service.agentPod("node-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "ksync-agent-node-1", pod.Name)
+	assert.Equal(t, "true", pod.Labels[agentLabel])
+	assert.WithinDuration(t, time.Now(), agentLastUsed(*pod), time.Minute)
+	assert.Equal(t, "ksync", pod.Labels["app"])
+	assert.NotContains(t, pod.Annotations, "forceUpdate")
+
+	terms := pod.Spec.Affinity.NodeAffinity.
+		RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	assert.Equal(t, []string{"node-1"}, terms[0].MatchFields[0].Values)
+
+	viper.Set("placement", PlacementOnDemand)
+	objects, err := service.Manifests(false)
+	require.NoError(t, err)
+	for _, obj := range objects {
+		assert.NotEqual(t, "DaemonSet", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+}
+
+func TestEnsureAgent(t *testing.T) {
+	client, restore := fakeCluster()
+	defer restore()
+
+	service := NewService()
+	pods := client.CoreV1().Pods("kube-system")
+
+	agents := func() []string {
+		list, err := pods.List(metav1.ListOptions{})
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, pod := range list.Items {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	require.NoError(t, service.EnsureAgent("node-1"))
+	require.NoError(t, service.EnsureAgent("node-1"))
+	assert.Equal(t, []string{"ksync-agent-node-1"}, agents())
+
+	// Another watch created it in the meantime.
+	client.PrependReactor("get", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.NewNotFound(
+				v1.Resource("pods"), action.(k8stesting.GetAction).GetName())
+		})
+	require.NoError(t, service.EnsureAgent("node-1"))
+	assert.Equal(t, []string{"ksync-agent-node-1"}, agents())
+	client.ReactionChain = client.ReactionChain[1:]
+
+	// Agents that stopped for good are replaced.
+	pod, err := pods.Get("ksync-agent-node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	pod.Status.Phase = v1.PodFailed
+	_, err = pods.UpdateStatus(pod)
+	require.NoError(t, err)
+
+	require.NoError(t, service.EnsureAgent("node-1"))
+	pod, err = pods.Get("ksync-agent-node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, pod.Status.Phase)
+}
+
+func TestNodeInUse(t *testing.T) {
+	defer delete(released, "agent-node")
+
+	assert.False(t, nodeInUse("agent-node", time.Minute))
+
+	conn := AcquireConnection("agent-node")
+	assert.True(t, nodeInUse("agent-node", time.Minute))
+
+	assert.NoError(t, conn.Release())
+	assert.True(t, nodeInUse("agent-node", time.Minute))
+	assert.False(t, nodeInUse("agent-node", 0))
+}
+
+func TestCollectAgents(t *testing.T) {
+	agent := func(name, node string, used time.Time) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: objectMeta("kube-system", name,
+				merge(map[string]string{agentLabel: "true"}, ksyncLabels)),
+			Spec: v1.PodSpec{NodeName: node},
+		}
+		pod.CreationTimestamp = metav1.NewTime(used)
+		pod.Annotations = map[string]string{
+			agentUsedAnnotation: used.UTC().Format(time.RFC3339),
+		}
+		return pod
+	}
+
+	old := time.Now().Add(-2 * agentGracePeriod)
+	_, restore := fakeCluster(
+		agent("unused", "node-1", old),
+		// Used by someone else's watch.
+		agent("elsewhere", "node-2", time.Now()),
+		agent("local", "node-3", old),
+	)
+	defer restore()
+
+	conn := AcquireConnection("node-3")
+	defer conn.Release() // nolint: errcheck
+
+	service := NewService()
+	require.NoError(t, service.collectAgents())
+
+	pods, err := Client.CoreV1().Pods("kube-system").List(metav1.ListOptions{})
+	require.NoError(t, err)
+
+	left := map[string]time.Time{}
+	for _, pod := range pods.Items {
+		left[pod.Name] = agentLastUsed(pod)
+	}
+	assert.Len(t, left, 2)
+	assert.Contains(t, left, "elsewhere")
+	assert.WithinDuration(t, time.Now(), left["local"], time.Minute)
+}
@@ -14,7 +14,7 @@ import (
 var (
 	// Client is used to communicate with the cluster's api server. Make sure to
 	// run InitKubeClient() first.
-	Client  *kubernetes.Clientset
+	Client  kubernetes.Interface
 	kubeCfg *rest.Config
 )
 
@@ -2,6 +2,8 @@ package cluster
 
 import (
 	"fmt"
+	"net"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -11,17 +13,42 @@ import (
 	"github.com/ksync/ksync/pkg/debug"
 )
 
+// The names of the tunnels to a ksync pod.
+const (
+	TunnelRadar             = "radar"
+	TunnelSyncthingAPI      = "syncthing-api"
+	TunnelSyncthingListener = "syncthing-listener"
+)
+
 var (
 	maxReadyRetries = uint64(10)
+
+	// nodeChecks are the readiness checks in progress for each node. Every
+	// folder starting on a node needs the ksync pod there to be ready, they
+	// wait on the same check instead of each polling the api server.
+	nodeChecks     = map[string]*nodeCheck{}
+	nodeChecksLock sync.Mutex
 )
 
+type nodeCheck struct {
+	done    chan struct{}
+	podName string
+	err     error
+}
+
 // Connection creates and manages the tunnels and gRPC connection between the
-// local host a ksync pod running on the remote cluster
+// local host a ksync pod running on the remote cluster. With a direct
+// transport, there are no tunnels and the node is connected to instead.
 type Connection struct {
 	NodeName string
 
-	service *Service
+	service  *Service
+	onChange func(TunnelEvent)
+
+	lock    sync.Mutex
 	tunnels []*Tunnel
+	host    string
+	stopped bool
 }
 
 // NewConnection is the constructor for Connection. You specify the node you'd
@@ -51,7 +78,22 @@ func (c *Connection) opts() []grpc.DialOption {
 	}
 }
 
+// With on demand placement, the agent on the node is started first and
+// waited for instead of the DaemonSet's pod.
 func (c *Connection) waitForHealthy() error {
+	readyBackoff := backoff.WithMaxRetries(
+		backoff.NewExponentialBackOff(), maxReadyRetries)
+
+	if OnDemand() {
+		if err := c.service.EnsureAgent(c.NodeName); err != nil {
+			return err
+		}
+
+		agentBackoff := backoff.NewExponentialBackOff()
+		agentBackoff.MaxElapsedTime = agentReadyTimeout
+		readyBackoff = agentBackoff
+	}
+
 	test := func() error {
 		ready, err := c.service.IsHealthy(c.NodeName)
 		if err != nil {
@@ -65,45 +107,153 @@ func (c *Connection) waitForHealthy() error {
 		return nil
 	}
 
-	return backoff.Retry(
-		test,
-		backoff.WithMaxRetries(backoff.NewExponentialBackOff(), maxReadyRetries))
+	return backoff.Retry(test, readyBackoff)
+}
+
+// ready waits for the ksync pod on this connection's node and returns its
+// name. Concurrent callers for the same node share a single check.
+func (c *Connection) ready() (string, error) {
+	nodeChecksLock.Lock()
+	check, ok := nodeChecks[c.NodeName]
+	if ok {
+		nodeChecksLock.Unlock()
+		<-check.done
+		return check.podName, check.err
+	}
+
+	check = &nodeCheck{done: make(chan struct{})}
+	nodeChecks[c.NodeName] = check
+	nodeChecksLock.Unlock()
+
+	if check.err = c.waitForHealthy(); check.err == nil {
+		check.podName, check.err = c.service.PodName(c.NodeName)
+	}
+
+	nodeChecksLock.Lock()
+	delete(nodeChecks, c.NodeName)
+	nodeChecksLock.Unlock()
+
+	close(check.done)
+
+	return check.podName, check.err
 }
 
 func (c *Connection) connection(port int32) (int32, error) {
-	if err := c.waitForHealthy(); err != nil {
-		return 0, err
+	podName, err := c.ready()
+	if err != nil {
+		return 0, debug.ErrorOut("ksync pod not ready", err, c)
 	}
 
-	podName, err := c.service.PodName(c.NodeName)
+	transport, err := Transport()
 	if err != nil {
-		return 0, debug.ErrorOut("cannot get pod name", err, c)
+		return 0, err
+	}
+
+	if transport != TransportPortForward {
+		return c.direct(transport, port)
 	}
 
 	tun := NewTunnel(c.service.Namespace, podName, port)
+	tun.name = c.portName(port)
+	tun.onChange = c.onChange
+	// The ksync pod might be replaced while the tunnel is down.
+	tun.resolvePod = c.ready
 
 	if err := tun.Start(); err != nil {
 		return 0, debug.ErrorOut("unable to start tunnel", err, c)
 	}
 
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Stopped while the tunnel was starting, nothing would close it later.
+	if c.stopped {
+		tun.Close()
+		return 0, fmt.Errorf("connection to %s stopped", c.NodeName)
+	}
+
 	c.tunnels = append(c.tunnels, tun)
 
-	return tun.LocalPort, nil
+	return tun.port(), nil
+}
+
+// direct returns the port to connect to on the node itself.
+func (c *Connection) direct(transport string, port int32) (int32, error) {
+	if c.Host() == "127.0.0.1" {
+		address, err := c.service.NodeAddress(c.NodeName)
+		if err != nil {
+			return 0, debug.ErrorOut("cannot get node address", err, c)
+		}
+
+		c.lock.Lock()
+		c.host = address
+		c.lock.Unlock()
+	}
+
+	return c.service.nodePort(transport, port)
+}
+
+// Host returns the host that the ports from Radar and Syncthing are on. This
+// is the local host for port-forwards and the node for direct transports.
+func (c *Connection) Host() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.host == "" {
+		return "127.0.0.1"
+	}
+
+	return c.host
+}
+
+// OnTunnelChange sets a function that is called whenever one of this
+// connection's tunnels goes down or comes back up. It applies to tunnels
+// started afterwards.
+func (c *Connection) OnTunnelChange(fn func(TunnelEvent)) {
+	c.onChange = fn
+}
+
+// tunnelList returns the tunnels that have been started so far.
+func (c *Connection) tunnelList() []*Tunnel {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]*Tunnel{}, c.tunnels...)
+}
+
+// portName describes what a remote port on the ksync pod is used for.
+func (c *Connection) portName(port int32) string {
+	switch port {
+	case c.service.RadarPort:
+		return TunnelRadar
+	case c.service.SyncthingAPI:
+		return TunnelSyncthingAPI
+	case c.service.SyncthingListener:
+		return TunnelSyncthingListener
+	}
+
+	return fmt.Sprintf("%d", port)
 }
 
 // Radar creates a new tunnel and gRPC connection to the radar container
 // running in the ksync pod specified by Container.NodeName
 func (c *Connection) Radar() (*grpc.ClientConn, error) {
-	localPort, err := c.connection(c.service.RadarPort)
+	port, err := c.connection(c.service.RadarPort)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	return grpc.Dial(fmt.Sprintf("127.0.0.1:%d", localPort), c.opts()...)
+	return grpc.Dial(c.address(port), c.opts()...)
+}
+
+// address is where a port handed out by this connection is reached.
+func (c *Connection) address(port int32) string {
+	return net.JoinHostPort(c.Host(), fmt.Sprintf("%d", port))
 }
 
 // Syncthing creates a tunnel for both the API and sync ports to the
-// syncthing container running in the ksync pod specified by Container.NodeName
+// syncthing container running in the ksync pod specified by Container.NodeName.
+// The ports are on Host.
 func (c *Connection) Syncthing() (int32, int32, error) {
 	apiPort, err := c.connection(c.service.SyncthingAPI)
 	if err != nil {
@@ -121,7 +271,11 @@ func (c *Connection) Syncthing() (int32, int32, error) {
 // Stop cleans all the established tunnels up. It should be called when this
 // connection is no longer needed.
 func (c *Connection) Stop() error {
-	for _, tun := range c.tunnels {
+	c.lock.Lock()
+	c.stopped = true
+	c.lock.Unlock()
+
+	for _, tun := range c.tunnelList() {
 		tun.Close()
 	}
 	log.WithFields(c.Fields()).Debug("stopped connection")
@@ -2,8 +2,10 @@ package cluster
 
 import (
 	"fmt"
+	"path/filepath"
 	"time"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -17,20 +19,70 @@ import (
 
 type creationFunc func(bool) error
 
+var hostPathDirectoryOrCreate = v1.HostPathDirectoryOrCreate
+
+// The syncthing API key is passed to syncthing from a Secret, through this
+// environment variable.
+const (
+	apiKeyEnv       = "KSYNC_APIKEY"
+	apiKeySecretKey = "apikey"
+)
+
+// Container filesystems are mounted by the runtime after ksync's pod has
+// started. Receiving mounts from the host makes them show up without
+// restarting syncthing.
+var hostToContainer = v1.MountPropagationHostToContainer
+
+// Without a PodSecurityPolicy, the namespace is labeled for Pod Security
+// Admission instead (before anything else, so that the pods are admitted).
+// Installs scoped to a namespace only create objects in that namespace. With
+// on demand placement, there is no DaemonSet, agents are started by watch.
 func (s *Service) creationFuncs(withPSP bool) []creationFunc {
-	funcs := []creationFunc{s.createDaemonSet, s.createServiceAccount}
+	funcs := []creationFunc{}
+	if !withPSP {
+		if namespaced() {
+			funcs = append(funcs, s.checkPodSecurity)
+		} else {
+			funcs = append(funcs, s.labelPodSecurity)
+		}
+	}
+	funcs = append(funcs, s.createAPIKeySecret)
+	if OnDemand() {
+		funcs = append(funcs, s.checkDaemonSet)
+	} else {
+		funcs = append(funcs, s.createDaemonSet)
+	}
+	funcs = append(funcs, s.createServiceAccount)
+	if transport, _ := Transport(); transport == TransportNodePort {
+		funcs = append(funcs, s.createNodePortService)
+	}
 	if withPSP {
-		funcs = append(funcs, s.createPSP, s.createClusterRole, s.createClusterRoleBinding)
+		if namespaced() {
+			funcs = append(funcs, s.createRole, s.createRoleBinding)
+		} else {
+			funcs = append(funcs, s.createPSP, s.createClusterRole, s.createClusterRoleBinding)
+		}
 	}
 	return funcs
 }
 
-func (s *Service) createDaemonSet(upgrade bool) error {
-	daemonSet := &appsv1.DaemonSet{
+// daemonSet is the DaemonSet that runs radar and syncthing on every node.
+func (s *Service) daemonSet() (*appsv1.DaemonSet, error) {
+	remote, err := GetRemoteConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DaemonSet",
+			APIVersion: "apps/v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: s.Namespace,
-			Name:      s.name,
-			Labels:    s.labels,
+			Namespace:   s.Namespace,
+			Name:        s.name,
+			Labels:      merge(remote.Labels, s.labels),
+			Annotations: remote.Annotations,
 		},
 		Spec: appsv1.DaemonSetSpec{
 			Selector: &metav1.LabelSelector{
@@ -38,21 +90,28 @@ func (s *Service) createDaemonSet(upgrade bool) error {
 			},
 			Template: v1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: s.labels,
-					Annotations: map[string]string{
+					Labels: merge(remote.Labels, s.labels),
+					Annotations: merge(remote.Annotations, map[string]string{
 						// TODO: this should only be set on --upgrade --force
 						"forceUpdate": fmt.Sprint(time.Now().Unix()),
 						// TODO: set inotify sysctl high en
-					},
+					}),
 				},
 				Spec: v1.PodSpec{
 					Containers: []v1.Container{
 						{
-							Name: s.name,
-							// TODO: configurable
+							Name:            s.name,
 							Image:           ImageName,
-							ImagePullPolicy: "Always",
-							Command:         []string{"/radar", "--log-level=debug", "serve"},
+							ImagePullPolicy: remote.ImagePullPolicy,
+							Command: []string{
+								"/radar",
+								"--log-level=debug",
+								"--docker-root", viper.GetString("docker-root"),
+								"--containerd-socket", viper.GetString("containerd-socket"),
+								"--containerd-root", viper.GetString("containerd-root"),
+								"--crio-socket", viper.GetString("crio-socket"),
+								"serve",
+							},
 							Env: []v1.EnvVar{
 								{
 									Name: "RADAR_POD_NAME",
@@ -63,36 +122,67 @@ func (s *Service) createDaemonSet(upgrade bool) error {
 									},
 								},
 							},
-							Ports: []v1.ContainerPort{
+							Ports: hostPorts([]v1.ContainerPort{
 								{ContainerPort: s.RadarPort, Name: "grpc"},
-							},
-							// TODO: resources
+							}),
+							Resources: remote.Resources,
 							VolumeMounts: []v1.VolumeMount{
 								{
 									Name:      "dockersock",
 									MountPath: viper.GetString("docker-socket"),
 								},
+								// Some storage drivers (btrfs) keep what is needed to find a
+								// container's filesystem in docker's root.
+								{
+									Name:             "dockerfs",
+									MountPath:        viper.GetString("docker-root"),
+									ReadOnly:         true,
+									MountPropagation: &hostToContainer,
+								},
+								{
+									Name:             "containerd",
+									MountPath:        viper.GetString("containerd-root"),
+									MountPropagation: &hostToContainer,
+								},
+								{
+									Name:      "crio",
+									MountPath: filepath.Dir(viper.GetString("crio-socket")),
+								},
 							},
 						},
 						{
 							Name:            "syncthing",
 							Image:           ImageName,
-							ImagePullPolicy: "Always",
+							ImagePullPolicy: remote.ImagePullPolicy,
 							Command: []string{
 								"/syncthing/syncthing",
 								"-home", "/var/syncthing/config",
-								"-gui-apikey", viper.GetString("apikey"),
+								"-gui-apikey", fmt.Sprintf("$(%s)", apiKeyEnv),
 								"-verbose",
 							},
-							Ports: []v1.ContainerPort{
+							Env: []v1.EnvVar{
+								{
+									Name: apiKeyEnv,
+									ValueFrom: &v1.EnvVarSource{
+										SecretKeyRef: &v1.SecretKeySelector{
+											LocalObjectReference: v1.LocalObjectReference{
+												Name: s.name,
+											},
+											Key: apiKeySecretKey,
+										},
+									},
+								},
+							},
+							Ports: hostPorts([]v1.ContainerPort{
 								{ContainerPort: s.SyncthingAPI, Name: "rest"},
 								{ContainerPort: s.SyncthingListener, Name: "sync"},
-							},
-							// TODO: resources
+							}),
+							Resources: remote.Resources,
 							VolumeMounts: []v1.VolumeMount{
 								v1.VolumeMount{
-									Name:      "dockerfs",
-									MountPath: viper.GetString("docker-root"),
+									Name:             "dockerfs",
+									MountPath:        viper.GetString("docker-root"),
+									MountPropagation: &hostToContainer,
 								},
 								v1.VolumeMount{
 									Name:      "dockersock",
@@ -102,6 +192,16 @@ func (s *Service) createDaemonSet(upgrade bool) error {
 									Name:      "kubelet",
 									MountPath: "/var/lib/kubelet",
 								},
+								v1.VolumeMount{
+									Name:             "containerd",
+									MountPath:        viper.GetString("containerd-root"),
+									MountPropagation: &hostToContainer,
+								},
+								v1.VolumeMount{
+									Name:             "criostorage",
+									MountPath:        viper.GetString("crio-root"),
+									MountPropagation: &hostToContainer,
+								},
 							},
 							LivenessProbe: &v1.Probe{
 								Handler: v1.Handler{
@@ -121,9 +221,11 @@ func (s *Service) createDaemonSet(upgrade bool) error {
 							},
 						},
 					},
-					NodeSelector: map[string]string{
-						"beta.kubernetes.io/os": "linux",
-					},
+					NodeSelector:       remote.NodeSelector,
+					Tolerations:        remote.Tolerations,
+					Affinity:           remote.Affinity,
+					ImagePullSecrets:   remote.pullSecrets(),
+					PriorityClassName:  remote.PriorityClassName,
 					ServiceAccountName: s.name,
 					// TODO: add HostPathType
 					Volumes: []v1.Volume{
@@ -151,6 +253,35 @@ func (s *Service) createDaemonSet(upgrade bool) error {
 								},
 							},
 						},
+						// Nodes only run one runtime, the directories for the others are
+						// created empty so that the pod can still start.
+						v1.Volume{
+							Name: "containerd",
+							VolumeSource: v1.VolumeSource{
+								HostPath: &v1.HostPathVolumeSource{
+									Path: viper.GetString("containerd-root"),
+									Type: &hostPathDirectoryOrCreate,
+								},
+							},
+						},
+						v1.Volume{
+							Name: "crio",
+							VolumeSource: v1.VolumeSource{
+								HostPath: &v1.HostPathVolumeSource{
+									Path: filepath.Dir(viper.GetString("crio-socket")),
+									Type: &hostPathDirectoryOrCreate,
+								},
+							},
+						},
+						v1.Volume{
+							Name: "criostorage",
+							VolumeSource: v1.VolumeSource{
+								HostPath: &v1.HostPathVolumeSource{
+									Path: viper.GetString("crio-root"),
+									Type: &hostPathDirectoryOrCreate,
+								},
+							},
+						},
 					},
 				},
 			},
@@ -158,6 +289,13 @@ func (s *Service) createDaemonSet(upgrade bool) error {
 				Type: "RollingUpdate",
 			},
 		},
+	}, nil
+}
+
+func (s *Service) createDaemonSet(upgrade bool) error {
+	daemonSet, err := s.daemonSet()
+	if err != nil {
+		return err
 	}
 
 	collection := Client.AppsV1().DaemonSets(s.Namespace)
@@ -176,14 +314,43 @@ func (s *Service) createDaemonSet(upgrade bool) error {
 	return nil
 }
 
-func (s *Service) createServiceAccount(upgrade bool) error {
-	serviceAccount := &v1.ServiceAccount{
+// checkDaemonSet warns about a DaemonSet left from before switching to on
+// demand placement. It is not removed, others might still be using it.
+func (s *Service) checkDaemonSet(_ bool) error {
+	_, err := Client.AppsV1().DaemonSets(s.Namespace).Get(
+		s.name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	log.WithFields(s.Fields()).Warnf(
+		"the %s DaemonSet still runs on every node, remove it with "+
+			"`kubectl --namespace %s delete daemonset %s` once nobody uses it",
+		s.name, s.Namespace, s.name)
+
+	return nil
+}
+
+// serviceAccount is the identity the DaemonSet's pods run as.
+func (s *Service) serviceAccount() *v1.ServiceAccount {
+	return &v1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: s.Namespace,
 			Name:      s.name,
 			Labels:    s.labels,
 		},
 	}
+}
+
+func (s *Service) createServiceAccount(upgrade bool) error {
+	serviceAccount := s.serviceAccount()
 
 	collection := Client.CoreV1().ServiceAccounts(s.Namespace)
 
@@ -201,8 +368,51 @@ func (s *Service) createServiceAccount(upgrade bool) error {
 	return nil
 }
 
-func (s *Service) createPSP(upgrade bool) error {
-	psp := &policyv1beta.PodSecurityPolicy{
+// apiKeySecret holds the key for syncthing's API, so that it is not part of
+// the DaemonSet.
+func (s *Service) apiKeySecret() *v1.Secret {
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      s.name,
+			Labels:    s.labels,
+		},
+		StringData: map[string]string{
+			apiKeySecretKey: viper.GetString("apikey"),
+		},
+	}
+}
+
+func (s *Service) createAPIKeySecret(upgrade bool) error {
+	secret := s.apiKeySecret()
+
+	collection := Client.CoreV1().Secrets(s.Namespace)
+
+	if _, err := collection.Create(secret); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	if upgrade {
+		if _, err := collection.Update(secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// podSecurityPolicy allows the DaemonSet to use host paths.
+func (s *Service) podSecurityPolicy() *policyv1beta.PodSecurityPolicy {
+	return &policyv1beta.PodSecurityPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodSecurityPolicy",
+			APIVersion: "policy/v1beta1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   s.name,
 			Labels: s.labels,
@@ -224,8 +434,13 @@ func (s *Service) createPSP(upgrade bool) error {
 				v1beta1.HostPath,
 				v1beta1.Secret,
 			},
+			HostPorts: s.pspHostPorts(),
 		},
 	}
+}
+
+func (s *Service) createPSP(upgrade bool) error {
+	psp := s.podSecurityPolicy()
 
 	collection := Client.PolicyV1beta1().PodSecurityPolicies()
 
@@ -243,8 +458,31 @@ func (s *Service) createPSP(upgrade bool) error {
 	return nil
 }
 
-func (s *Service) createClusterRole(upgrade bool) error {
-	clusterRole := &rbacv1.ClusterRole{
+func (s *Service) pspHostPorts() []policyv1beta.HostPortRange {
+	if transport, _ := Transport(); transport != TransportHostPort {
+		return nil
+	}
+
+	ranges := []policyv1beta.HostPortRange{}
+	for _, port := range []int32{
+		s.RadarPort, s.SyncthingAPI, s.SyncthingListener} {
+
+		ranges = append(ranges, policyv1beta.HostPortRange{
+			Min: port,
+			Max: port,
+		})
+	}
+
+	return ranges
+}
+
+// clusterRole grants the use of the PodSecurityPolicy.
+func (s *Service) clusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRole",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   s.name,
 			Labels: s.labels,
@@ -258,6 +496,10 @@ func (s *Service) createClusterRole(upgrade bool) error {
 			},
 		},
 	}
+}
+
+func (s *Service) createClusterRole(upgrade bool) error {
+	clusterRole := s.clusterRole()
 
 	collection := Client.RbacV1().ClusterRoles()
 
@@ -275,8 +517,13 @@ func (s *Service) createClusterRole(upgrade bool) error {
 	return nil
 }
 
-func (s *Service) createClusterRoleBinding(upgrade bool) error {
-	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+// clusterRoleBinding grants the ClusterRole to the service account.
+func (s *Service) clusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   s.name,
 			Labels: s.labels,
@@ -292,6 +539,10 @@ func (s *Service) createClusterRoleBinding(upgrade bool) error {
 			Namespace: s.Namespace,
 		}},
 	}
+}
+
+func (s *Service) createClusterRoleBinding(upgrade bool) error {
+	clusterRoleBinding := s.clusterRoleBinding()
 
 	collection := Client.RbacV1().ClusterRoleBindings()
 
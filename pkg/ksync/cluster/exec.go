@@ -0,0 +1,143 @@
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// restartTimeout is how long a container gets to restart after its main
+// process has been signalled.
+var restartTimeout = 10 * time.Second
+
+// Exec runs a command in a container, the same way `kubectl exec` does. When
+// stdin is not nil, it is streamed to the command. The command's stdout is
+// returned, stderr is part of the error when the command fails.
+func Exec(
+	namespace string,
+	podName string,
+	containerName string,
+	command []string,
+	stdin io.Reader) ([]byte, error) {
+
+	req := Client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: containerName,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	log.WithFields(log.Fields{
+		"namespace": namespace,
+		"pod":       podName,
+		"container": containerName,
+		"command":   strings.Join(command, " "),
+	}).Debug("running command in container")
+
+	executor, err := remotecommand.NewSPDYExecutor(kubeCfg, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := executor.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// DeletePod deletes a pod, its controller is expected to replace it.
+func DeletePod(namespace string, podName string) error {
+	return Client.CoreV1().Pods(namespace).Delete(
+		podName, &metav1.DeleteOptions{})
+}
+
+// RestartContainer restarts a container by signalling its main process from
+// inside the container. Processes running as PID 1 ignore the signal unless
+// they handle it, when the container has not restarted after restartTimeout
+// the pod is deleted instead.
+func RestartContainer(
+	namespace string, podName string, containerName string) error {
+
+	restarts, err := containerRestarts(namespace, podName, containerName)
+	if err != nil {
+		return err
+	}
+
+	fields := log.Fields{
+		"namespace": namespace,
+		"pod":       podName,
+		"container": containerName,
+	}
+
+	// The exec session goes away with the process, an error does not mean the
+	// signal was not delivered.
+	if _, err := Exec(
+		namespace, podName, containerName, []string{"kill", "1"}, nil); err != nil {
+		log.WithFields(fields).Debug(err)
+	}
+
+	restartBackoff := backoff.NewExponentialBackOff()
+	restartBackoff.MaxElapsedTime = restartTimeout
+
+	restarted := func() error {
+		current, err := containerRestarts(namespace, podName, containerName)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		if current <= restarts {
+			return fmt.Errorf("container %s has not restarted", containerName)
+		}
+
+		return nil
+	}
+
+	if err := backoff.Retry(restarted, restartBackoff); err != nil {
+		log.WithFields(fields).Debugf("deleting pod instead: %v", err)
+		return DeletePod(namespace, podName)
+	}
+
+	return nil
+}
+
+// containerRestarts is the restart count of a container in a pod.
+func containerRestarts(
+	namespace string, podName string, containerName string) (int32, error) {
+
+	pod, err := Client.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return status.RestartCount, nil
+		}
+	}
+
+	return 0, fmt.Errorf("container %s not found in %s", containerName, podName)
+}
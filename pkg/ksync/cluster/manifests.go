@@ -0,0 +1,117 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// The formats manifests can be rendered in.
+const (
+	ManifestYAML = "yaml"
+	ManifestJSON = "json"
+)
+
+// Manifests returns everything that Run would create on the cluster, in the
+// order it should be applied. Agents started on demand are not included, nor
+// is the Secret holding the syncthing API key (see Render). The cluster isn't
+// asked whether it serves PodSecurityPolicy, without withPSP the namespace is
+// labeled for Pod Security Admission instead.
+func (s *Service) Manifests(withPSP bool) ([]runtime.Object, error) {
+	daemonSet, err := s.daemonSet()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rendered manifests end up in version control, they should only change
+	// when something has actually changed.
+	delete(daemonSet.Spec.Template.Annotations, "forceUpdate")
+
+	objects := []runtime.Object{}
+
+	// Installs scoped to a namespace don't label it, see checkPodSecurity.
+	if !withPSP && !namespaced() {
+		objects = append(objects, s.podSecurityNamespace())
+	}
+
+	objects = append(objects, s.serviceAccount())
+
+	if withPSP {
+		if namespaced() {
+			objects = append(objects, s.role(), s.roleBinding())
+		} else {
+			objects = append(objects,
+				s.podSecurityPolicy(), s.clusterRole(), s.clusterRoleBinding())
+		}
+	}
+
+	if !OnDemand() {
+		objects = append(objects, daemonSet)
+	}
+
+	if transport, _ := Transport(); transport == TransportNodePort {
+		objects = append(objects, s.nodePortService())
+	}
+
+	return objects, nil
+}
+
+// Render writes the manifests to w without touching the cluster. YAML is
+// written as multiple documents, JSON as a List.
+func (s *Service) Render(w io.Writer, withPSP bool, format string) error {
+	objects, err := s.Manifests(withPSP)
+	if err != nil {
+		return err
+	}
+
+	// Rendered manifests end up in version control, the API key does not.
+	log.Warnf(
+		"the %s Secret is not part of the manifests, create it with: "+
+			"kubectl --namespace %s create secret generic %s --from-literal=%s=<apikey>",
+		s.name, s.Namespace, s.name, apiKeySecretKey)
+
+	switch format {
+	case ManifestYAML:
+		for _, obj := range objects {
+			buf, err := yaml.Marshal(obj)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(w, "---\n%s", buf); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case ManifestJSON:
+		list := &v1.List{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "List",
+				APIVersion: "v1",
+			},
+		}
+		for _, obj := range objects {
+			list.Items = append(list.Items, runtime.RawExtension{Object: obj})
+		}
+
+		buf, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "%s\n", buf)
+		return err
+	}
+
+	return fmt.Errorf(
+		"unsupported output %s, must be one of: %s, %s",
+		format, ManifestYAML, ManifestJSON)
+}
@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifests(t *testing.T) {
+	service := NewService()
+
+	// Labeled for Pod Security Admission instead of a PodSecurityPolicy.
+	objects, err := service.Manifests(false)
+	require.NoError(t, err)
+	assert.Len(t, objects, 3)
+	assert.Equal(t, service.podSecurityNamespace(), objects[0])
+
+	objects, err = service.Manifests(true)
+	require.NoError(t, err)
+	assert.Len(t, objects, 5)
+
+	for _, obj := range objects {
+		kind := obj.GetObjectKind().GroupVersionKind()
+		assert.NotEmpty(t, kind.Kind)
+		assert.NotEmpty(t, kind.Version)
+	}
+}
+
+func TestRender(t *testing.T) {
+	service := NewService()
+
+	var out bytes.Buffer
+	require.NoError(t, service.Render(&out, true, ManifestYAML))
+	assert.Equal(t, 5, strings.Count(out.String(), "---\n"))
+	assert.Contains(t, out.String(), "kind: DaemonSet")
+	assert.NotContains(t, out.String(), "forceUpdate")
+
+	out.Reset()
+	require.NoError(t, service.Render(&out, false, ManifestJSON))
+	assert.Contains(t, out.String(), `"kind": "List"`)
+	assert.Contains(t, out.String(), `"kind": "DaemonSet"`)
+
+	assert.Error(t, service.Render(&out, false, "toml"))
+}
+
+func TestRenderAPIKey(t *testing.T) {
+	defer viper.Set("apikey", "")
+	viper.Set("apikey", "not-in-manifests")
+
+	service := NewService()
+
+	var out bytes.Buffer
+	require.NoError(t, service.Render(&out, true, ManifestYAML))
+	assert.NotContains(t, out.String(), "not-in-manifests")
+	assert.NotContains(t, out.String(), "kind: Secret")
+	assert.Contains(t, out.String(), "$(KSYNC_APIKEY)")
+
+	assert.Equal(t,
+		"not-in-manifests", service.apiKeySecret().StringData[apiKeySecretKey])
+}
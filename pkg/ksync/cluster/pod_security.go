@@ -0,0 +1,199 @@
+package cluster
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// podSecurityEnforceLabel is the namespace label that Pod Security
+	// Admission enforces.
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	// podSecurityPrivileged is the level the DaemonSet needs, it uses host
+	// paths.
+	podSecurityPrivileged = "privileged"
+)
+
+var podSecurityLevelError = `Namespace (%s) enforces the %s pod security level, the ksync DaemonSet needs %s.
+
+- Label the namespace with '%s=%s'.
+- Or install ksync into another namespace with --daemonset-namespace.`
+
+// PSPSupported checks whether the cluster still serves PodSecurityPolicy. It
+// was removed in Kubernetes 1.25, Pod Security Admission replaces it.
+func PSPSupported() (bool, error) {
+	resources, err := Client.Discovery().ServerResourcesForGroupVersion(
+		"policy/v1beta1")
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Name == "podsecuritypolicies" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// usePSP decides whether a PodSecurityPolicy is created. It has to be wanted
+// and the cluster has to support it.
+func usePSP(withPSP bool) (bool, error) {
+	if !withPSP {
+		return false, nil
+	}
+
+	supported, err := PSPSupported()
+	if err != nil {
+		return false, err
+	}
+
+	if !supported {
+		log.Info(
+			"PodSecurityPolicy is not served by this cluster, using Pod Security Admission instead")
+	}
+
+	return supported, nil
+}
+
+// podSecurityLevel returns the level Pod Security Admission enforces in the
+// DaemonSet's namespace, empty when the namespace is not labeled.
+func (s *Service) podSecurityLevel() (string, error) {
+	namespace, err := Client.CoreV1().Namespaces().Get(
+		s.Namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return namespace.Labels[podSecurityEnforceLabel], nil
+}
+
+// podSecurityNamespace is the label labelPodSecurity adds to the DaemonSet's
+// namespace, as a manifest.
+func (s *Service) podSecurityNamespace() *v1.Namespace {
+	return &v1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Namespace",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: s.Namespace,
+			Labels: map[string]string{
+				podSecurityEnforceLabel: podSecurityPrivileged,
+			},
+		},
+	}
+}
+
+// labelPodSecurity allows the DaemonSet's pods in its namespace with Pod
+// Security Admission. Namespaces without a level get the privileged one, a
+// stricter level that has been set on purpose is not overridden.
+func (s *Service) labelPodSecurity(upgrade bool) error {
+	collection := Client.CoreV1().Namespaces()
+
+	namespace, err := collection.Get(s.Namespace, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	switch level := namespace.Labels[podSecurityEnforceLabel]; level {
+	case podSecurityPrivileged:
+		return nil
+	case "":
+	default:
+		return fmt.Errorf(
+			podSecurityLevelError,
+			s.Namespace,
+			level,
+			podSecurityPrivileged,
+			podSecurityEnforceLabel,
+			podSecurityPrivileged)
+	}
+
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	namespace.Labels[podSecurityEnforceLabel] = podSecurityPrivileged
+
+	log.WithFields(log.Fields{
+		"namespace": s.Namespace,
+		"level":     podSecurityPrivileged,
+	}).Debug("labeling namespace for pod security admission")
+
+	_, err = collection.Update(namespace)
+	return err
+}
+
+// PodSecurity describes how the DaemonSet is admitted to the cluster. It is an
+// error when Pod Security Admission would reject its pods.
+func (s *Service) PodSecurity() (string, error) {
+	supported, err := PSPSupported()
+	if err != nil {
+		return "", err
+	}
+
+	if supported {
+		_, err := Client.PolicyV1beta1().PodSecurityPolicies().Get(
+			s.name, metav1.GetOptions{})
+		if err == nil {
+			return fmt.Sprintf("PodSecurityPolicy (%s)", s.name), nil
+		}
+
+		// Installs scoped to a namespace can't see the cluster wide policy.
+		if errors.IsForbidden(err) && namespaced() {
+			return fmt.Sprintf(
+				"PodSecurityPolicy (%s), granted by a Role in %s",
+				s.name, s.Namespace), nil
+		}
+
+		if !errors.IsNotFound(err) {
+			return "", err
+		}
+	}
+
+	level, err := s.podSecurityLevel()
+	if err != nil {
+		if errors.IsForbidden(err) && namespaced() {
+			return fmt.Sprintf(
+				"Pod Security Admission, the level of %s can't be read",
+				s.Namespace), nil
+		}
+		return "", err
+	}
+
+	switch level {
+	case podSecurityPrivileged:
+		return fmt.Sprintf(
+			"Pod Security Admission (%s enforces %s)", s.Namespace, level), nil
+	case "":
+		// Older clusters have neither, or the PodSecurityPolicy is managed some
+		// other way (--psp=false).
+		if supported {
+			return fmt.Sprintf(
+				"No PodSecurityPolicy from ksync, %s has no pod security level",
+				s.Namespace), nil
+		}
+
+		// Pod Security Admission's own default is privileged, the cluster
+		// might be configured with another one.
+		return fmt.Sprintf(
+			"Pod Security Admission (%s has no level, the cluster default applies)",
+			s.Namespace), nil
+	}
+
+	return "", fmt.Errorf(
+		podSecurityLevelError,
+		s.Namespace,
+		level,
+		podSecurityPrivileged,
+		podSecurityEnforceLabel,
+		podSecurityPrivileged)
+}
@@ -0,0 +1,44 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodSecurity(t *testing.T) {
+	client, restore := fakeCluster(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-system"},
+	})
+	defer restore()
+
+	// Newer clusters serve policy/v1beta1 without PodSecurityPolicy.
+	client.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "policy/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets"}},
+	}}
+
+	service := NewService()
+
+	described, err := service.PodSecurity()
+	require.NoError(t, err)
+	assert.Contains(t, described, "cluster default")
+
+	require.NoError(t, service.labelPodSecurity(false))
+	described, err = service.PodSecurity()
+	require.NoError(t, err)
+	assert.Equal(t,
+		"Pod Security Admission (kube-system enforces privileged)", described)
+
+	namespace := service.podSecurityNamespace()
+	namespace.Labels[podSecurityEnforceLabel] = "restricted"
+	_, err = client.CoreV1().Namespaces().Update(namespace)
+	require.NoError(t, err)
+
+	_, err = service.PodSecurity()
+	assert.Error(t, err)
+	assert.Error(t, service.labelPodSecurity(false))
+}
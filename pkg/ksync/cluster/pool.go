@@ -0,0 +1,352 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/ksync/ksync/pkg/debug"
+)
+
+var (
+	errConnectionReleased = fmt.Errorf("connection released while connecting")
+
+	pool     = map[string]*NodeConnection{}
+	poolLock sync.Mutex
+
+	// released is when the last connection to a node, that is no longer in the
+	// pool, was released.
+	released = map[string]time.Time{}
+)
+
+// NodeConnection is a connection to the ksync pod on a node that is shared by
+// everything syncing with that node. There is a single radar connection and a
+// single pair of syncthing tunnels, no matter how many folders there are.
+// Connections are reference counted, every AcquireConnection needs a Release.
+type NodeConnection struct {
+	NodeName string
+
+	// radarLock and syncthingLock are held while connecting, so that only one
+	// caller connects and the others wait for it. lock is not held while
+	// connecting, everything else about the connection stays available.
+	radarLock     sync.Mutex
+	syncthingLock sync.Mutex
+
+	lock       sync.Mutex
+	refs       int
+	connection *Connection
+
+	radarConn    *grpc.ClientConn
+	apiPort      int32
+	listenerPort int32
+
+	hooks    map[int]func(TunnelEvent)
+	nextHook int
+}
+
+// TunnelStatus is the health of a single tunnel to a node.
+type TunnelStatus struct {
+	Name       string
+	LocalPort  int32
+	RemotePort int32
+	Healthy    bool
+}
+
+// AcquireConnection returns the connection for a node, creating it if there
+// is none yet.
+func AcquireConnection(nodeName string) *NodeConnection {
+	poolLock.Lock()
+	defer poolLock.Unlock()
+
+	conn, ok := pool[nodeName]
+	if !ok {
+		conn = &NodeConnection{
+			NodeName:   nodeName,
+			connection: NewConnection(nodeName),
+			hooks:      map[int]func(TunnelEvent){},
+		}
+		conn.connection.OnTunnelChange(conn.tunnelChanged)
+		pool[nodeName] = conn
+		delete(released, nodeName)
+	}
+
+	conn.lock.Lock()
+	conn.refs++
+	conn.lock.Unlock()
+
+	return conn
+}
+
+// Connections returns every connection that is currently in use, sorted by
+// node name.
+func Connections() []*NodeConnection {
+	poolLock.Lock()
+	defer poolLock.Unlock()
+
+	conns := []*NodeConnection{}
+	for _, conn := range pool {
+		conns = append(conns, conn)
+	}
+
+	sort.Slice(conns, func(i, j int) bool {
+		return conns[i].NodeName < conns[j].NodeName
+	})
+
+	return conns
+}
+
+// nodeInUse is true when a node has a connection, or had one in the last
+// grace period.
+func nodeInUse(nodeName string, grace time.Duration) bool {
+	poolLock.Lock()
+	defer poolLock.Unlock()
+
+	if _, ok := pool[nodeName]; ok {
+		return true
+	}
+
+	at, ok := released[nodeName]
+	return ok && time.Since(at) < grace
+}
+
+func (n *NodeConnection) String() string {
+	return debug.YamlString(n)
+}
+
+// Fields returns a set of structured fields for logging.
+func (n *NodeConnection) Fields() log.Fields {
+	return log.Fields{
+		"node": n.NodeName,
+		"refs": n.Refs(),
+	}
+}
+
+// Refs returns how many users there are of this connection.
+func (n *NodeConnection) Refs() int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.refs
+}
+
+// Radar returns the shared gRPC connection to radar, starting it if required.
+func (n *NodeConnection) Radar() (*grpc.ClientConn, error) {
+	n.radarLock.Lock()
+	defer n.radarLock.Unlock()
+
+	n.lock.Lock()
+	conn := n.radarConn
+	n.lock.Unlock()
+
+	if conn != nil {
+		return conn, nil
+	}
+
+	conn, err := n.connection.Radar()
+	if err != nil {
+		return nil, err
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// Released while connecting, nothing would close it later.
+	if n.refs <= 0 {
+		conn.Close() // nolint: errcheck
+		return nil, errConnectionReleased
+	}
+
+	n.radarConn = conn
+
+	return conn, nil
+}
+
+// Syncthing returns the local ports for the syncthing API and listener
+// tunnels, starting them if required.
+func (n *NodeConnection) Syncthing() (int32, int32, error) {
+	n.syncthingLock.Lock()
+	defer n.syncthingLock.Unlock()
+
+	n.lock.Lock()
+	apiPort, listenerPort := n.apiPort, n.listenerPort
+	n.lock.Unlock()
+
+	if apiPort != 0 {
+		return apiPort, listenerPort, nil
+	}
+
+	apiPort, listenerPort, err := n.connection.Syncthing()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// The tunnels were closed by Release.
+	if n.refs <= 0 {
+		return 0, 0, errConnectionReleased
+	}
+
+	n.apiPort = apiPort
+	n.listenerPort = listenerPort
+
+	return apiPort, listenerPort, nil
+}
+
+// Host returns the host that the ports handed out by this connection are on.
+func (n *NodeConnection) Host() string {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.connection.Host()
+}
+
+// OnChange adds a function that is called whenever a tunnel to the node goes
+// down or comes back up. The returned function removes it again.
+func (n *NodeConnection) OnChange(hook func(TunnelEvent)) func() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	id := n.nextHook
+	n.nextHook++
+	n.hooks[id] = hook
+
+	return func() {
+		n.lock.Lock()
+		defer n.lock.Unlock()
+
+		delete(n.hooks, id)
+	}
+}
+
+// When a tunnel comes back on another port, the ports handed out so far are
+// stale. The radar connection is dialed again on the new port before anyone
+// is told about it, the tunnel itself is not replaced.
+func (n *NodeConnection) tunnelChanged(event TunnelEvent) {
+	if event.Healthy && event.PortChanged() && event.Name == TunnelRadar {
+		n.redialRadar(event.LocalPort)
+	}
+
+	n.lock.Lock()
+
+	if event.Healthy && event.PortChanged() {
+		switch event.Name {
+		case TunnelSyncthingAPI:
+			n.apiPort = event.LocalPort
+		case TunnelSyncthingListener:
+			n.listenerPort = event.LocalPort
+		}
+	}
+
+	hooks := []func(TunnelEvent){}
+	for _, hook := range n.hooks {
+		hooks = append(hooks, hook)
+	}
+	n.lock.Unlock()
+
+	log.WithFields(log.Fields{
+		"node":    n.NodeName,
+		"tunnel":  event.Name,
+		"healthy": event.Healthy,
+		"port":    event.LocalPort,
+	}).Debug("tunnel changed")
+
+	for _, hook := range hooks {
+		hook(event)
+	}
+}
+
+// redialRadar replaces the radar connection with one to port. The dial does
+// not block, gRPC connects in the background and the tunnel is already up.
+func (n *NodeConnection) redialRadar(port int32) {
+	n.radarLock.Lock()
+	defer n.radarLock.Unlock()
+
+	n.lock.Lock()
+	connected := n.radarConn != nil
+	n.lock.Unlock()
+
+	// Radar has not been asked for yet, it is dialed on the new port then.
+	if !connected {
+		return
+	}
+
+	conn, err := grpc.Dial(n.connection.address(port), grpc.WithInsecure())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"node": n.NodeName,
+			"port": port,
+		}).Warnf("unable to reconnect to radar: %v", err)
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// Released in the meantime, the previous connection is closed already.
+	if n.refs <= 0 {
+		conn.Close() // nolint: errcheck
+		return
+	}
+
+	n.radarConn.Close() // nolint: errcheck
+	n.radarConn = conn
+}
+
+// Tunnels reports on the health of every tunnel that has been started.
+func (n *NodeConnection) Tunnels() []TunnelStatus {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	status := []TunnelStatus{}
+	for _, tun := range n.connection.tunnelList() {
+		status = append(status, TunnelStatus{
+			Name:       n.connection.portName(tun.RemotePort),
+			LocalPort:  tun.port(),
+			RemotePort: tun.RemotePort,
+			Healthy:    tun.Healthy(),
+		})
+	}
+
+	return status
+}
+
+// Release gives up a reference to the connection. Once nothing is using it,
+// the tunnels are closed and it is removed from the pool.
+func (n *NodeConnection) Release() error {
+	poolLock.Lock()
+	defer poolLock.Unlock()
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.refs--
+	if n.refs > 0 {
+		return nil
+	}
+
+	if pool[n.NodeName] == n {
+		delete(pool, n.NodeName)
+		released[n.NodeName] = time.Now()
+	}
+
+	var err error
+	if n.radarConn != nil {
+		err = n.radarConn.Close()
+	}
+
+	if stopErr := n.connection.Stop(); err == nil {
+		err = stopErr
+	}
+
+	log.WithFields(log.Fields{
+		"node": n.NodeName,
+	}).Debug("released connection")
+
+	return err
+}
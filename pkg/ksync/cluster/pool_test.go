@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestConnectionPool(t *testing.T) {
+	first := AcquireConnection("node")
+	second := AcquireConnection("node")
+	other := AcquireConnection("other")
+
+	// Folders on the same node share a connection.
+	assert.True(t, first == second)
+	assert.False(t, first == other)
+	assert.Equal(t, 2, first.Refs())
+	assert.Len(t, Connections(), 2)
+	assert.Equal(t, "node", Connections()[0].NodeName)
+
+	assert.NoError(t, first.Release())
+	assert.Len(t, Connections(), 2)
+
+	assert.NoError(t, second.Release())
+	assert.NoError(t, other.Release())
+	assert.Len(t, Connections(), 0)
+
+	// Once released, a new connection is created.
+	third := AcquireConnection("node")
+	assert.False(t, first == third)
+	assert.NoError(t, third.Release())
+}
+
+func TestConnectionTunnelChanged(t *testing.T) {
+	conn := AcquireConnection("node")
+	defer conn.Release() // nolint: errcheck
+
+	conn.apiPort = 1000
+	conn.listenerPort = 2000
+
+	events := []TunnelEvent{}
+	remove := conn.OnChange(func(event TunnelEvent) {
+		events = append(events, event)
+	})
+
+	conn.tunnelChanged(TunnelEvent{
+		Name: TunnelSyncthingListener, LocalPort: 2000, PreviousPort: 2000})
+	conn.tunnelChanged(TunnelEvent{
+		Name: TunnelSyncthingListener, Healthy: true, LocalPort: 2001, PreviousPort: 2000})
+
+	apiPort, listenerPort, err := conn.Syncthing()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1000), apiPort)
+	assert.Equal(t, int32(2001), listenerPort)
+	assert.Len(t, events, 2)
+	assert.True(t, events[1].PortChanged())
+
+	remove()
+	conn.tunnelChanged(TunnelEvent{
+		Name: TunnelSyncthingAPI, Healthy: true, LocalPort: 1000, PreviousPort: 1000})
+	assert.Len(t, events, 2)
+}
+
+func TestConnectionRadarMoved(t *testing.T) {
+	conn := AcquireConnection("node")
+	defer conn.Release() // nolint: errcheck
+
+	radar, err := grpc.Dial("127.0.0.1:1000", grpc.WithInsecure())
+	require.NoError(t, err)
+	conn.radarConn = radar
+
+	conn.tunnelChanged(TunnelEvent{
+		Name: TunnelRadar, Healthy: true, LocalPort: 1001, PreviousPort: 1000})
+
+	moved, err := conn.Radar()
+	assert.NoError(t, err)
+	assert.False(t, radar == moved)
+	assert.Equal(t, "127.0.0.1:1001", moved.Target())
+	assert.Empty(t, conn.Tunnels())
+}
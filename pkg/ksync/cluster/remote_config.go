@@ -0,0 +1,146 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/viper"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/ksync/ksync/pkg/cli"
+)
+
+var defaultNodeSelector = map[string]string{
+	"beta.kubernetes.io/os": "linux",
+}
+
+// RemoteConfig customizes the DaemonSet. It is read from the `remote` section
+// of the config file and applied by `ksync init` (and `init --upgrade` for an
+// existing installation). The fields use the same names and format as the
+// pod spec.
+type RemoteConfig struct {
+	ImagePullPolicy   v1.PullPolicy           `json:"imagePullPolicy,omitempty"`
+	ImagePullSecrets  []string                `json:"imagePullSecrets,omitempty"`
+	Resources         v1.ResourceRequirements `json:"resources,omitempty"`
+	Tolerations       []v1.Toleration         `json:"tolerations,omitempty"`
+	NodeSelector      map[string]string       `json:"nodeSelector,omitempty"`
+	Affinity          *v1.Affinity            `json:"affinity,omitempty"`
+	PriorityClassName string                  `json:"priorityClassName,omitempty"`
+	Labels            map[string]string       `json:"labels,omitempty"`
+	Annotations       map[string]string       `json:"annotations,omitempty"`
+}
+
+// GetRemoteConfig reads the `remote` section of the config. It is read from
+// the config file directly, viper would lower case the keys of labels,
+// annotations and the node selector.
+func GetRemoteConfig() (*RemoteConfig, error) {
+	raw, err := cli.ConfigSection("remote")
+	if err != nil {
+		return nil, err
+	}
+
+	if raw == nil {
+		raw = viper.Get("remote")
+	}
+
+	return parseRemoteConfig(raw)
+}
+
+// parseRemoteConfig reads the remote section of the config, as it was written
+// in the config file or as viper has it.
+func parseRemoteConfig(raw interface{}) (*RemoteConfig, error) {
+	cfg := &RemoteConfig{}
+
+	if raw == nil {
+		return cfg.withDefaults(), nil
+	}
+
+	// The config is converted to json so that the k8s types (eg. resource
+	// quantities) parse the way they do everywhere else.
+	buf, err := json.Marshal(stringKeys(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(buf, cfg); err != nil {
+		return nil, fmt.Errorf("invalid remote config: %v", err)
+	}
+
+	switch cfg.ImagePullPolicy {
+	case "", v1.PullAlways, v1.PullIfNotPresent, v1.PullNever:
+	default:
+		return nil, fmt.Errorf(
+			"invalid remote config: unsupported imagePullPolicy %s",
+			cfg.ImagePullPolicy)
+	}
+
+	return cfg.withDefaults(), nil
+}
+
+func (c *RemoteConfig) withDefaults() *RemoteConfig {
+	if c.ImagePullPolicy == "" {
+		c.ImagePullPolicy = v1.PullAlways
+	}
+
+	nodeSelector := map[string]string{}
+	for k, v := range defaultNodeSelector {
+		nodeSelector[k] = v
+	}
+	for k, v := range c.NodeSelector {
+		nodeSelector[k] = v
+	}
+	c.NodeSelector = nodeSelector
+
+	return c
+}
+
+// pullSecrets returns the pull secrets for the pod spec.
+func (c *RemoteConfig) pullSecrets() []v1.LocalObjectReference {
+	secrets := []v1.LocalObjectReference{}
+	for _, name := range c.ImagePullSecrets {
+		secrets = append(secrets, v1.LocalObjectReference{Name: name})
+	}
+
+	return secrets
+}
+
+// merge combines the extra labels or annotations with the ones ksync needs,
+// which always win.
+func merge(extra map[string]string, required map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range extra {
+		result[k] = v
+	}
+	for k, v := range required {
+		result[k] = v
+	}
+
+	return result
+}
+
+// Nested sections in the config come back from yaml with interface keys,
+// which json cannot handle.
+func stringKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for k, val := range v {
+			result[fmt.Sprint(k)] = stringKeys(val)
+		}
+		return result
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for k, val := range v {
+			result[k] = stringKeys(val)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, val := range v {
+			result[i] = stringKeys(val)
+		}
+		return result
+	}
+
+	return value
+}
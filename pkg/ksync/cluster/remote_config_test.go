@@ -0,0 +1,89 @@
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/ksync/ksync/pkg/cli"
+)
+
+func TestGetRemoteConfig(t *testing.T) {
+	defer viper.Set("remote", nil)
+
+	cfg, err := GetRemoteConfig()
+	require.NoError(t, err)
+	assert.Equal(t, v1.PullAlways, cfg.ImagePullPolicy)
+	assert.Equal(t, defaultNodeSelector, cfg.NodeSelector)
+
+	// This is what comes back from yaml for nested sections.
+	viper.Set("remote", map[string]interface{}{
+		"imagepullpolicy":  "IfNotPresent",
+		"imagepullsecrets": []interface{}{"registry"},
+		"resources": map[interface{}]interface{}{
+			"limits": map[interface{}]interface{}{"memory": "512Mi"},
+		},
+		"tolerations": []interface{}{
+			map[interface{}]interface{}{
+				"key":      "nvidia.com/gpu",
+				"operator": "Exists",
+				"effect":   "NoSchedule",
+			},
+		},
+		"nodeselector": map[interface{}]interface{}{"pool": "gpu"},
+	})
+
+	cfg, err = GetRemoteConfig()
+	require.NoError(t, err)
+	assert.Equal(t, v1.PullIfNotPresent, cfg.ImagePullPolicy)
+	assert.Equal(t,
+		[]v1.LocalObjectReference{{Name: "registry"}}, cfg.pullSecrets())
+	assert.Equal(t,
+		resource.MustParse("512Mi"), cfg.Resources.Limits[v1.ResourceMemory])
+	require.Len(t, cfg.Tolerations, 1)
+	assert.Equal(t, v1.TolerationOpExists, cfg.Tolerations[0].Operator)
+	assert.Equal(t, "gpu", cfg.NodeSelector["pool"])
+	assert.Equal(t, "linux", cfg.NodeSelector["beta.kubernetes.io/os"])
+
+	viper.Set("remote", map[string]interface{}{"imagepullpolicy": "Sometimes"})
+	_, err = GetRemoteConfig()
+	assert.Error(t, err)
+}
+
+func TestGetRemoteConfigKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ksync")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	cfgPath := filepath.Join(dir, "ksync.yaml")
+	require.NoError(t, ioutil.WriteFile(cfgPath, []byte(`
+remote:
+  nodeSelector:
+    Pool: gpu
+  labels:
+    Team: platform
+`), 0644))
+
+	raw, err := cli.ReadConfigSection(cfgPath, "remote")
+	require.NoError(t, err)
+
+	cfg, err := parseRemoteConfig(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "gpu", cfg.NodeSelector["Pool"])
+	assert.Equal(t, map[string]string{"Team": "platform"}, cfg.Labels)
+}
+
+func TestMergeLabels(t *testing.T) {
+	labels := merge(
+		map[string]string{"team": "ml", "app": "mine"},
+		map[string]string{"app": "ksync"})
+
+	assert.Equal(t, map[string]string{"team": "ml", "app": "ksync"}, labels)
+}
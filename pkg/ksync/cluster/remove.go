@@ -0,0 +1,275 @@
+package cluster
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ksync/ksync/pkg/debug"
+)
+
+// Resource is an object on the cluster that ksync created.
+type Resource struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (r Resource) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s/%s", r.Kind, r.Name)
+	}
+
+	return fmt.Sprintf("%s/%s (%s)", r.Kind, r.Name, r.Namespace)
+}
+
+// resourceKind lists and deletes one kind of object that ksync creates.
+type resourceKind struct {
+	kind string
+	// clusterWide kinds are not in a namespace.
+	clusterWide bool
+	list        func(opts metav1.ListOptions) (runtime.Object, error)
+	delete      func(namespace, name string) error
+}
+
+// The kinds are in the order they are deleted, the pods go first so that they
+// are not left without permissions. Namespaced kinds are listed
+// in listNamespace, metav1.NamespaceAll is every namespace.
+func resourceKinds(listNamespace string) []resourceKind {
+	core := Client.CoreV1()
+	apps := Client.AppsV1()
+	rbac := Client.RbacV1()
+	policy := Client.PolicyV1beta1()
+	opts := &metav1.DeleteOptions{}
+
+	return []resourceKind{
+		{
+			kind: "Pod",
+			// Only the agents, the DaemonSet's pods go with it.
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				o.LabelSelector = labels.SelectorFromSet(
+					labels.Set{agentLabel: "true"}).String() + "," + o.LabelSelector
+				return core.Pods(listNamespace).List(o)
+			},
+			delete: func(namespace, name string) error {
+				return core.Pods(namespace).Delete(name, opts)
+			},
+		},
+		{
+			kind: "DaemonSet",
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return apps.DaemonSets(listNamespace).List(o)
+			},
+			delete: func(namespace, name string) error {
+				return apps.DaemonSets(namespace).Delete(name, opts)
+			},
+		},
+		{
+			kind: "Secret",
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return core.Secrets(listNamespace).List(o)
+			},
+			delete: func(namespace, name string) error {
+				return core.Secrets(namespace).Delete(name, opts)
+			},
+		},
+		{
+			kind: "Service",
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return core.Services(listNamespace).List(o)
+			},
+			delete: func(namespace, name string) error {
+				return core.Services(namespace).Delete(name, opts)
+			},
+		},
+		{
+			kind: "RoleBinding",
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return rbac.RoleBindings(listNamespace).List(o)
+			},
+			delete: func(namespace, name string) error {
+				return rbac.RoleBindings(namespace).Delete(name, opts)
+			},
+		},
+		{
+			kind: "Role",
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return rbac.Roles(listNamespace).List(o)
+			},
+			delete: func(namespace, name string) error {
+				return rbac.Roles(namespace).Delete(name, opts)
+			},
+		},
+		{
+			kind:        "ClusterRoleBinding",
+			clusterWide: true,
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return rbac.ClusterRoleBindings().List(o)
+			},
+			delete: func(_, name string) error {
+				return rbac.ClusterRoleBindings().Delete(name, opts)
+			},
+		},
+		{
+			kind:        "ClusterRole",
+			clusterWide: true,
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return rbac.ClusterRoles().List(o)
+			},
+			delete: func(_, name string) error {
+				return rbac.ClusterRoles().Delete(name, opts)
+			},
+		},
+		{
+			kind:        "PodSecurityPolicy",
+			clusterWide: true,
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return policy.PodSecurityPolicies().List(o)
+			},
+			delete: func(_, name string) error {
+				return policy.PodSecurityPolicies().Delete(name, opts)
+			},
+		},
+		{
+			kind: "ServiceAccount",
+			list: func(o metav1.ListOptions) (runtime.Object, error) {
+				return core.ServiceAccounts(listNamespace).List(o)
+			},
+			delete: func(namespace, name string) error {
+				return core.ServiceAccounts(namespace).Delete(name, opts)
+			},
+		},
+	}
+}
+
+func (s *Service) resourceKinds() []resourceKind {
+	if namespaced() {
+		return resourceKinds(s.Namespace)
+	}
+
+	return resourceKinds(metav1.NamespaceAll)
+}
+
+// Resources returns everything on the cluster that has ksync's labels, in
+// every namespace. Kinds that the cluster does not serve (eg.
+// PodSecurityPolicy on newer versions) are skipped. Installs scoped to a
+// namespace only look in the service's namespace.
+func (s *Service) Resources() ([]Resource, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(s.labels).String(),
+	}
+
+	resources := []Resource{}
+	for _, kind := range s.resourceKinds() {
+		if kind.clusterWide && namespaced() {
+			continue
+		}
+
+		list, err := kind.list(opts)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				return nil, err
+			}
+
+			resources = append(resources, Resource{
+				Kind:      kind.kind,
+				Namespace: accessor.GetNamespace(),
+				Name:      accessor.GetName(),
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// Remove deletes everything that ksync created on the cluster (see
+// Resources), returning what was deleted. With dryRun, nothing is deleted and
+// the result is what would have been. Only run this when you want to clean
+// everything up.
+func (s *Service) Remove(dryRun bool) ([]Resource, error) {
+	resources, err := s.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return resources, nil
+	}
+
+	kinds := map[string]resourceKind{}
+	for _, kind := range s.resourceKinds() {
+		kinds[kind.kind] = kind
+	}
+
+	removed := []Resource{}
+	for _, resource := range resources {
+		if err := kinds[resource.Kind].delete(
+			resource.Namespace, resource.Name); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return removed, err
+		}
+
+		log.WithFields(debug.MergeFields(s.Fields(), log.Fields{
+			"resource": resource.String(),
+		})).Debug("removed resource")
+
+		removed = append(removed, resource)
+	}
+
+	return removed, nil
+}
+
+// Leftovers returns the resources with ksync's labels that do not belong to
+// the current installation. They come from older installs, either in another
+// namespace or left behind by a clean that only removed the DaemonSet.
+// Installations from rendered manifests might use other names, only the
+// namespace is compared.
+func (s *Service) Leftovers() ([]Resource, error) {
+	resources, err := s.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	// On demand, there is no DaemonSet to tell the current install apart.
+	installedKind := "DaemonSet"
+	if OnDemand() {
+		installedKind = "ServiceAccount"
+	}
+
+	installed := false
+	for _, resource := range resources {
+		if resource.Kind == installedKind && resource.Namespace == s.Namespace {
+			installed = true
+		}
+	}
+
+	leftovers := []Resource{}
+	for _, resource := range resources {
+		current := resource.Namespace == "" || resource.Namespace == s.Namespace
+		if !installed || !current {
+			leftovers = append(leftovers, resource)
+		}
+	}
+
+	return leftovers, nil
+}
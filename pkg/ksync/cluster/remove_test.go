@@ -0,0 +1,158 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var ksyncLabels = map[string]string{"name": "ksync", "app": "ksync"}
+
+// fakeCluster points Client at a fake clientset with objects, until the
+// returned function is called.
+func fakeCluster(objects ...runtime.Object) (*fake.Clientset, func()) {
+	previous := Client
+	viper.Set("daemonset-namespace", "kube-system")
+
+	client := fake.NewSimpleClientset(objects...)
+	Client = client
+
+	return client, func() {
+		Client = previous
+		viper.Set("daemonset-namespace", "")
+	}
+}
+
+func objectMeta(namespace, name string, labels map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}
+}
+
+// installed is a current install, a DaemonSet left in another namespace by an
+// older one and something that isn't ksync's.
+func installed() []runtime.Object {
+	agentLabels := merge(map[string]string{agentLabel: "true"}, ksyncLabels)
+
+	return []runtime.Object{
+		&v1.ServiceAccount{ObjectMeta: objectMeta("kube-system", "ksync", ksyncLabels)},
+		&v1.Secret{ObjectMeta: objectMeta("kube-system", "ksync", ksyncLabels)},
+		&rbacv1.ClusterRole{ObjectMeta: objectMeta("", "ksync", ksyncLabels)},
+		&appsv1.DaemonSet{ObjectMeta: objectMeta("kube-system", "ksync", ksyncLabels)},
+		&appsv1.DaemonSet{ObjectMeta: objectMeta("old", "ksync", ksyncLabels)},
+		&appsv1.DaemonSet{ObjectMeta: objectMeta("kube-system", "other", nil)},
+		// The DaemonSet's pods go with it, only agents are removed.
+		&v1.Pod{ObjectMeta: objectMeta("kube-system", "ksync-abcde", ksyncLabels)},
+		&v1.Pod{ObjectMeta: objectMeta("kube-system", "ksync-fghij", agentLabels)},
+	}
+}
+
+func TestResourceString(t *testing.T) {
+	assert.Equal(t,
+		"DaemonSet/ksync (kube-system)",
+		Resource{Kind: "DaemonSet", Namespace: "kube-system", Name: "ksync"}.String())
+	assert.Equal(t,
+		"ClusterRole/ksync",
+		Resource{Kind: "ClusterRole", Name: "ksync"}.String())
+}
+
+func TestResources(t *testing.T) {
+	_, restore := fakeCluster(installed()...)
+	defer restore()
+
+	resources, err := NewService().Resources()
+	require.NoError(t, err)
+
+	// In the order they are removed.
+	assert.Equal(t, []Resource{
+		{Kind: "Pod", Namespace: "kube-system", Name: "ksync-fghij"},
+		{Kind: "DaemonSet", Namespace: "kube-system", Name: "ksync"},
+		{Kind: "DaemonSet", Namespace: "old", Name: "ksync"},
+		{Kind: "Secret", Namespace: "kube-system", Name: "ksync"},
+		{Kind: "ClusterRole", Name: "ksync"},
+		{Kind: "ServiceAccount", Namespace: "kube-system", Name: "ksync"},
+	}, resources)
+}
+
+func TestRemove(t *testing.T) {
+	client, restore := fakeCluster(installed()...)
+	defer restore()
+
+	service := NewService()
+
+	expected, err := service.Resources()
+	require.NoError(t, err)
+	client.ClearActions()
+
+	removed, err := service.Remove(true)
+	require.NoError(t, err)
+	assert.Equal(t, expected, removed)
+	for _, action := range client.Actions() {
+		assert.Equal(t, "list", action.GetVerb())
+	}
+
+	client.ClearActions()
+
+	removed, err = service.Remove(false)
+	require.NoError(t, err)
+	assert.Equal(t, expected, removed)
+
+	deleted := []Resource{}
+	for _, action := range client.Actions() {
+		if del, ok := action.(k8stesting.DeleteAction); ok {
+			deleted = append(deleted, Resource{
+				Kind:      kindOf(del.GetResource().Resource),
+				Namespace: del.GetNamespace(),
+				Name:      del.GetName(),
+			})
+		}
+	}
+	assert.Equal(t, expected, deleted)
+
+	left, err := service.Resources()
+	require.NoError(t, err)
+	assert.Empty(t, left)
+
+	_, err = client.AppsV1().DaemonSets("kube-system").Get(
+		"other", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestLeftovers(t *testing.T) {
+	_, restore := fakeCluster(installed()...)
+	defer restore()
+
+	service := NewService()
+
+	leftovers, err := service.Leftovers()
+	require.NoError(t, err)
+	assert.Equal(t, []Resource{
+		{Kind: "DaemonSet", Namespace: "old", Name: "ksync"},
+	}, leftovers)
+
+	// Without the current DaemonSet, everything is left over.
+	require.NoError(t, Client.AppsV1().DaemonSets("kube-system").Delete(
+		"ksync", &metav1.DeleteOptions{}))
+
+	leftovers, err = service.Leftovers()
+	require.NoError(t, err)
+	assert.Len(t, leftovers, 5)
+}
+
+// kindOf maps the resources used in the fake's actions to kinds.
+func kindOf(resource string) string {
+	return map[string]string{
+		"pods":            "Pod",
+		"daemonsets":      "DaemonSet",
+		"secrets":         "Secret",
+		"clusterroles":    "ClusterRole",
+		"serviceaccounts": "ServiceAccount",
+	}[resource]
+}
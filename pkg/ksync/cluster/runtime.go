@@ -0,0 +1,20 @@
+package cluster
+
+import (
+	"strings"
+)
+
+// DefaultRuntime is the container runtime assumed when a container ID does not
+// include one.
+var DefaultRuntime = "docker"
+
+// ContainerRuntime splits a container ID from a pod's status into the runtime
+// and the runtime's ID for the container (eg. `containerd://<id>`).
+func ContainerRuntime(containerID string) (string, string) {
+	parts := strings.SplitN(containerID, "://", 2)
+	if len(parts) != 2 {
+		return DefaultRuntime, containerID
+	}
+
+	return parts[0], parts[1]
+}
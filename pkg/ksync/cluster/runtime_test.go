@@ -0,0 +1,21 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerRuntime(t *testing.T) {
+	runtime, id := ContainerRuntime("containerd://abc123")
+	assert.Equal(t, "containerd", runtime)
+	assert.Equal(t, "abc123", id)
+
+	runtime, id = ContainerRuntime("docker://abc123")
+	assert.Equal(t, "docker", runtime)
+	assert.Equal(t, "abc123", id)
+
+	runtime, id = ContainerRuntime("abc123")
+	assert.Equal(t, DefaultRuntime, runtime)
+	assert.Equal(t, "abc123", id)
+}
@@ -0,0 +1,156 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// How much of the cluster ksync is installed into. By default it needs
+// cluster-admin, the DaemonSet goes into kube-system and is granted its
+// PodSecurityPolicy cluster wide. Installs scoped to a namespace only touch
+// objects in the user's namespace, so that they work with namespace-only RBAC.
+const (
+	ScopeCluster   = "cluster"
+	ScopeNamespace = "namespace"
+)
+
+// Scopes are the supported install scopes.
+var Scopes = []string{
+	ScopeCluster,
+	ScopeNamespace,
+}
+
+// Scope returns the configured install scope, the default is cluster.
+func Scope() (string, error) {
+	scope := viper.GetString("scope")
+	if scope == "" {
+		return ScopeCluster, nil
+	}
+
+	for _, valid := range Scopes {
+		if scope == valid {
+			return scope, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"unsupported scope %s, must be one of: %s",
+		scope,
+		strings.Join(Scopes, ", "))
+}
+
+// namespaced is true when the install is limited to the service's namespace.
+func namespaced() bool {
+	scope, _ := Scope()
+	return scope == ScopeNamespace
+}
+
+// role grants the use of the PodSecurityPolicy in the service's namespace. The
+// policy itself is cluster wide, it has to be created by a cluster admin (eg.
+// from `init --dry-run`).
+func (s *Service) role() *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Role",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      s.name,
+			Labels:    s.labels,
+		},
+		Rules: s.clusterRole().Rules,
+	}
+}
+
+func (s *Service) createRole(upgrade bool) error {
+	role := s.role()
+
+	collection := Client.RbacV1().Roles(s.Namespace)
+
+	if _, err := collection.Create(role); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	if upgrade {
+		if _, err := collection.Update(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roleBinding grants the Role to the service account.
+func (s *Service) roleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      s.name,
+			Labels:    s.labels,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     s.name,
+		},
+		Subjects: s.clusterRoleBinding().Subjects,
+	}
+}
+
+func (s *Service) createRoleBinding(upgrade bool) error {
+	roleBinding := s.roleBinding()
+
+	collection := Client.RbacV1().RoleBindings(s.Namespace)
+
+	if _, err := collection.Create(roleBinding); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	if upgrade {
+		if _, err := collection.Update(roleBinding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPodSecurity is labelPodSecurity for installs scoped to a namespace.
+// Namespaces are cluster wide and can't be labeled, a stricter level is still
+// reported before the DaemonSet's pods get rejected.
+func (s *Service) checkPodSecurity(_ bool) error {
+	level, err := s.podSecurityLevel()
+	if err != nil {
+		if errors.IsForbidden(err) {
+			log.WithFields(s.Fields()).Debug(
+				"cannot read namespace, skipping pod security check")
+			return nil
+		}
+		return err
+	}
+
+	if level == "" || level == podSecurityPrivileged {
+		return nil
+	}
+
+	return fmt.Errorf(
+		podSecurityLevelError,
+		s.Namespace,
+		level,
+		podSecurityPrivileged,
+		podSecurityEnforceLabel,
+		podSecurityPrivileged)
+}
@@ -0,0 +1,44 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	defer viper.Set("scope", "")
+
+	scope, err := Scope()
+	assert.NoError(t, err)
+	assert.Equal(t, ScopeCluster, scope)
+
+	viper.Set("scope", "galaxy")
+	_, err = Scope()
+	assert.Error(t, err)
+}
+
+func TestNamespacedManifests(t *testing.T) {
+	defer viper.Set("scope", "")
+	defer viper.Set("namespace", "")
+
+	viper.Set("scope", ScopeNamespace)
+	viper.Set("namespace", "dev")
+
+	service := NewService()
+	assert.Equal(t, "dev", service.Namespace)
+
+	objects, err := service.Manifests(true)
+	require.NoError(t, err)
+
+	kinds := []string{}
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	assert.Equal(t,
+		[]string{"ServiceAccount", "Role", "RoleBinding", "DaemonSet"}, kinds)
+
+	assert.Equal(t, "dev", service.roleBinding().Subjects[0].Namespace)
+}
@@ -10,6 +10,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/ksync/ksync/pkg/debug"
 	pb "github.com/ksync/ksync/pkg/proto"
@@ -46,9 +47,15 @@ func (s *Service) Fields() log.Fields {
 }
 
 // NewService constructs a Service to track the ksync daemonset on the cluster.
+// Installs scoped to a namespace (see Scope) run in the user's namespace.
 func NewService() *Service {
+	namespace := viper.GetString("daemonset-namespace")
+	if namespaced() {
+		namespace = viper.GetString("namespace")
+	}
+
 	return &Service{
-		Namespace: viper.GetString("daemonset-namespace"),
+		Namespace: namespace,
 		name:      "ksync",
 		labels: map[string]string{
 			"name": "ksync",
@@ -61,19 +68,40 @@ func NewService() *Service {
 	}
 }
 
-// IsInstalled makes sure the cluster service has been installed.
+// IsInstalled makes sure the cluster service has been installed. The service
+// might have been installed from rendered manifests (see Render) under
+// another name, any DaemonSet with the expected labels counts. With on demand
+// placement, there is only the service account for the agents.
 func (s *Service) IsInstalled() (bool, error) {
 	// TODO: add version checking here.
-	if _, err := Client.AppsV1().DaemonSets(s.Namespace).Get(
-		s.name, metav1.GetOptions{}); err != nil {
-		if !errors.IsNotFound(err) {
-			return false, err
+	if OnDemand() {
+		_, err := Client.CoreV1().ServiceAccounts(s.Namespace).Get(
+			s.name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
 		}
+		return err == nil, err
+	}
 
-		return false, nil
+	daemonSets := Client.AppsV1().DaemonSets(s.Namespace)
+
+	_, err := daemonSets.Get(s.name, metav1.GetOptions{})
+	if err == nil {
+		return true, nil
 	}
 
-	return true, nil
+	if !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	list, err := daemonSets.List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(s.labels).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(list.Items) > 0, nil
 }
 
 // PodName takes the name of a node and returns the name of the ksync pod
@@ -111,6 +139,30 @@ func (s *Service) PodName(nodeName string) (string, error) {
 	return pods.Items[0].Name, nil
 }
 
+// Runtime returns the name of the container runtime (eg. docker, containerd)
+// used on a node. This comes from the ksync pod running on that node.
+func (s *Service) Runtime(nodeName string) (string, error) {
+	podName, err := s.PodName(nodeName)
+	if err != nil {
+		return "", err
+	}
+
+	pod, err := Client.CoreV1().Pods(s.Namespace).Get(
+		podName, metav1.GetOptions{})
+	if err != nil {
+		return "", debug.ErrorOut("cannot get pod details", err, s)
+	}
+
+	if len(pod.Status.ContainerStatuses) == 0 ||
+		pod.Status.ContainerStatuses[0].ContainerID == "" {
+		return "", fmt.Errorf("%s has no running containers", podName)
+	}
+
+	runtime, _ := ContainerRuntime(pod.Status.ContainerStatuses[0].ContainerID)
+
+	return runtime, nil
+}
+
 // IsHealthy verifies the target node is running the service container and it
 // is not scheduled for deletion.
 func (s *Service) IsHealthy(nodeName string) (bool, error) {
@@ -182,9 +234,16 @@ func (s *Service) NodeNames() ([]string, error) {
 	return result, nil
 }
 
-// Run starts (or upgrades) the ksync daemonset on the remote cluster.
+// Run starts (or upgrades) the ksync daemonset on the remote cluster. A
+// PodSecurityPolicy is only created when withPSP is set and the cluster still
+// supports them, Pod Security Admission is used otherwise.
 func (s *Service) Run(upgrade, withPSP bool) error {
-	for _, f := range s.creationFuncs(true) {
+	withPSP, err := usePSP(withPSP)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range s.creationFuncs(withPSP) {
 		if err := f(upgrade); err != nil {
 			return err
 		}
@@ -230,19 +289,3 @@ func (s *Service) Version() (*pb.VersionInfo, error) {
 
 	return nil, fmt.Errorf("no healthy nodes found")
 }
-
-// Remove provides cleanup for the ksync daemonset on the cluster. Only run this
-// when you want to clean everything up.
-func (s *Service) Remove() error {
-	daemonSets := Client.AppsV1().DaemonSets(s.Namespace)
-
-	if err := daemonSets.Delete(s.name, &metav1.DeleteOptions{}); err != nil {
-		return err
-	}
-
-	log.WithFields(debug.MergeFields(s.Fields(), log.Fields{
-		"Name": s.name,
-	})).Debug("Removed DaemonSet")
-
-	return nil
-}
@@ -0,0 +1,597 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// SidecarName is the name of the syncthing container added to pods.
+	SidecarName = "ksync-syncthing"
+
+	// sidecarAnnotation lists the specs that need the sidecar on a workload.
+	// Once the last one is gone, the sidecar is removed again.
+	sidecarAnnotation = "ksync.github.io/sidecar-specs"
+)
+
+// ErrSidecarPending is returned while pods with the sidecar are being rolled
+// out.
+var ErrSidecarPending = fmt.Errorf(
+	"waiting for pods with the %s sidecar to be rolled out", SidecarName)
+
+// workload is a controller whose pod template can be patched.
+type workload struct {
+	kind     string
+	name     string
+	meta     *metav1.ObjectMeta
+	template *v1.PodTemplateSpec
+	update   func() error
+}
+
+func getWorkload(namespace, kind, name string) (*workload, error) {
+	apps := Client.AppsV1()
+
+	switch kind {
+	case "Deployment":
+		obj, err := apps.Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return deploymentWorkload(obj), nil
+	case "StatefulSet":
+		obj, err := apps.StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return statefulSetWorkload(obj), nil
+	case "DaemonSet":
+		obj, err := apps.DaemonSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return daemonSetWorkload(obj), nil
+	}
+
+	return nil, fmt.Errorf("the sidecar cannot be added to a %s", kind)
+}
+
+func deploymentWorkload(obj *appsv1.Deployment) *workload {
+	return &workload{
+		kind:     "Deployment",
+		name:     obj.Name,
+		meta:     &obj.ObjectMeta,
+		template: &obj.Spec.Template,
+		update: func() error {
+			_, err := Client.AppsV1().Deployments(obj.Namespace).Update(obj)
+			return err
+		},
+	}
+}
+
+func statefulSetWorkload(obj *appsv1.StatefulSet) *workload {
+	return &workload{
+		kind:     "StatefulSet",
+		name:     obj.Name,
+		meta:     &obj.ObjectMeta,
+		template: &obj.Spec.Template,
+		update: func() error {
+			_, err := Client.AppsV1().StatefulSets(obj.Namespace).Update(obj)
+			return err
+		},
+	}
+}
+
+func daemonSetWorkload(obj *appsv1.DaemonSet) *workload {
+	return &workload{
+		kind:     "DaemonSet",
+		name:     obj.Name,
+		meta:     &obj.ObjectMeta,
+		template: &obj.Spec.Template,
+		update: func() error {
+			_, err := Client.AppsV1().DaemonSets(obj.Namespace).Update(obj)
+			return err
+		},
+	}
+}
+
+// podWorkload finds the controller that manages a pod. Pods of a deployment
+// are owned by a ReplicaSet, which is owned by the deployment.
+func podWorkload(pod *v1.Pod) (*workload, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil, fmt.Errorf(
+			"%s is not managed by a controller, the sidecar cannot be added", pod.Name)
+	}
+
+	if owner.Kind != "ReplicaSet" {
+		return getWorkload(pod.Namespace, owner.Kind, owner.Name)
+	}
+
+	rs, err := Client.AppsV1().ReplicaSets(pod.Namespace).Get(
+		owner.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	owner = metav1.GetControllerOf(rs)
+	if owner == nil {
+		return nil, fmt.Errorf(
+			"%s is not managed by a deployment, the sidecar cannot be added", rs.Name)
+	}
+
+	return getWorkload(pod.Namespace, owner.Kind, owner.Name)
+}
+
+// listWorkloads returns every workload in a namespace that has the sidecar.
+func listWorkloads(namespace string) ([]*workload, error) {
+	apps := Client.AppsV1()
+	workloads := []*workload{}
+
+	deployments, err := apps.Deployments(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		workloads = append(workloads, deploymentWorkload(&deployments.Items[i]))
+	}
+
+	statefulSets, err := apps.StatefulSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		workloads = append(workloads, statefulSetWorkload(&statefulSets.Items[i]))
+	}
+
+	daemonSets, err := apps.DaemonSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range daemonSets.Items {
+		workloads = append(workloads, daemonSetWorkload(&daemonSets.Items[i]))
+	}
+
+	withSidecar := []*workload{}
+	for _, w := range workloads {
+		if _, ok := w.meta.Annotations[sidecarAnnotation]; ok {
+			withSidecar = append(withSidecar, w)
+		}
+	}
+
+	return withSidecar, nil
+}
+
+func (w *workload) specs() []string {
+	value := w.meta.Annotations[sidecarAnnotation]
+	if value == "" {
+		return []string{}
+	}
+
+	return strings.Split(value, ",")
+}
+
+func (w *workload) setSpecs(specs []string) {
+	sort.Strings(specs)
+
+	if len(specs) == 0 {
+		delete(w.meta.Annotations, sidecarAnnotation)
+		return
+	}
+
+	if w.meta.Annotations == nil {
+		w.meta.Annotations = map[string]string{}
+	}
+	w.meta.Annotations[sidecarAnnotation] = strings.Join(specs, ",")
+}
+
+// volumeFor returns the volume mount of a container that remotePath is on.
+// The sidecar mounts the same volume to share the files.
+func volumeFor(
+	containers []v1.Container,
+	containerName string,
+	remotePath string) (*v1.VolumeMount, error) {
+
+	for _, cntr := range containers {
+		if containerName != "" && cntr.Name != containerName {
+			continue
+		}
+
+		var found *v1.VolumeMount
+		for i, mount := range cntr.VolumeMounts {
+			if remotePath != mount.MountPath &&
+				!strings.HasPrefix(remotePath, strings.TrimSuffix(mount.MountPath, "/")+"/") {
+				continue
+			}
+
+			if found == nil || len(mount.MountPath) > len(found.MountPath) {
+				found = &cntr.VolumeMounts[i]
+			}
+		}
+
+		if found == nil {
+			return nil, fmt.Errorf(
+				"%s is not on a volume of %s, it cannot be shared with the sidecar",
+				remotePath, cntr.Name)
+		}
+
+		return found, nil
+	}
+
+	return nil, fmt.Errorf("container %s not found", containerName)
+}
+
+func sidecarContainer() v1.Container {
+	service := NewService()
+
+	return v1.Container{
+		Name:            SidecarName,
+		Image:           ImageName,
+		ImagePullPolicy: "Always",
+		Command: []string{
+			"/syncthing/syncthing",
+			"-home", "/var/syncthing/config",
+			"-gui-apikey", viper.GetString("apikey"),
+			"-verbose",
+		},
+		Ports: []v1.ContainerPort{
+			{ContainerPort: service.SyncthingAPI, Name: "ksync-rest"},
+			{ContainerPort: service.SyncthingListener, Name: "ksync-sync"},
+		},
+		ReadinessProbe: &v1.Probe{
+			Handler: v1.Handler{
+				TCPSocket: &v1.TCPSocketAction{
+					Port: intstr.FromInt(int(service.SyncthingAPI)),
+				},
+			},
+		},
+	}
+}
+
+// addSidecar adds the sidecar to the template, mounting the volume. It
+// returns whether anything changed.
+func (w *workload) addSidecar(mount v1.VolumeMount) bool {
+	spec := &w.template.Spec
+
+	// The sidecar writes to the volume, even when the app only reads it.
+	mount.ReadOnly = false
+
+	for i := range spec.Containers {
+		cntr := &spec.Containers[i]
+		if cntr.Name != SidecarName {
+			continue
+		}
+
+		for _, existing := range cntr.VolumeMounts {
+			if existing.Name == mount.Name && existing.MountPath == mount.MountPath {
+				return false
+			}
+		}
+
+		cntr.VolumeMounts = append(cntr.VolumeMounts, mount)
+		return true
+	}
+
+	sidecar := sidecarContainer()
+	sidecar.VolumeMounts = []v1.VolumeMount{mount}
+	spec.Containers = append(spec.Containers, sidecar)
+
+	return true
+}
+
+func (w *workload) removeSidecar() {
+	spec := &w.template.Spec
+
+	containers := []v1.Container{}
+	for _, cntr := range spec.Containers {
+		if cntr.Name != SidecarName {
+			containers = append(containers, cntr)
+		}
+	}
+	spec.Containers = containers
+}
+
+func hasSidecar(pod *v1.Pod, mount *v1.VolumeMount) (bool, bool) {
+	for _, cntr := range pod.Spec.Containers {
+		if cntr.Name != SidecarName {
+			continue
+		}
+
+		for _, existing := range cntr.VolumeMounts {
+			if existing.Name != mount.Name || existing.MountPath != mount.MountPath {
+				continue
+			}
+
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.Name == SidecarName {
+					return true, status.Ready
+				}
+			}
+
+			return true, false
+		}
+	}
+
+	return false, false
+}
+
+// EnsureSidecar makes sure that a pod runs syncthing in a sidecar that shares
+// the volume remotePath is on. Pods cannot be changed once they are running,
+// the sidecar is added to the pod's controller instead. ErrSidecarPending is
+// returned until the pod has a ready sidecar, new pods replace this one
+// once the controller has been patched.
+func EnsureSidecar(
+	namespace string,
+	podName string,
+	containerName string,
+	remotePath string,
+	specName string) error {
+
+	pod, err := Client.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	mount, err := volumeFor(pod.Spec.Containers, containerName, remotePath)
+	if err != nil {
+		return err
+	}
+
+	w, err := podWorkload(pod)
+	if err != nil {
+		return err
+	}
+
+	specs := w.specs()
+	hasSpec := false
+	for _, name := range specs {
+		if name == specName {
+			hasSpec = true
+		}
+	}
+
+	// A sidecar added for another spec is shared, the spec is recorded all the
+	// same so that the sidecar stays until the last spec using it is removed.
+	found, ready := hasSidecar(pod, mount)
+
+	changed := false
+	if !found {
+		changed = w.addSidecar(*mount)
+	}
+	if !hasSpec {
+		w.setSpecs(append(specs, specName))
+	}
+
+	if changed || !hasSpec {
+		log.WithFields(log.Fields{
+			"kind": w.kind,
+			"name": w.name,
+			"spec": specName,
+		}).Info("adding sidecar")
+
+		if err := w.update(); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return ErrSidecarPending
+	}
+
+	if !ready {
+		return fmt.Errorf("%s sidecar in %s is not ready", SidecarName, podName)
+	}
+
+	return nil
+}
+
+// RemoveSidecar removes a spec from every workload in the namespace that has
+// the sidecar. The sidecar itself is removed along with the last spec using
+// it.
+func RemoveSidecar(namespace string, specName string) error {
+	workloads, err := listWorkloads(namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range workloads {
+		specs := []string{}
+		found := false
+		for _, name := range w.specs() {
+			if name == specName {
+				found = true
+				continue
+			}
+			specs = append(specs, name)
+		}
+
+		if !found {
+			continue
+		}
+
+		w.setSpecs(specs)
+		if len(specs) == 0 {
+			w.removeSidecar()
+		}
+
+		log.WithFields(log.Fields{
+			"kind": w.kind,
+			"name": w.name,
+			"spec": specName,
+		}).Info("removing sidecar")
+
+		if err := w.update(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveSidecars removes the sidecar from every workload that has it, no
+// matter which specs it is for, returning the workloads. With dryRun, nothing
+// is changed and the result is what would have been. Installs scoped to a
+// namespace only look in the service's namespace.
+func (s *Service) RemoveSidecars(dryRun bool) ([]Resource, error) {
+	namespace := metav1.NamespaceAll
+	if namespaced() {
+		namespace = s.Namespace
+	}
+
+	workloads, err := listWorkloads(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []Resource{}
+	for _, w := range workloads {
+		if !dryRun {
+			w.setSpecs(nil)
+			w.removeSidecar()
+
+			if err := w.update(); err != nil {
+				return removed, err
+			}
+		}
+
+		removed = append(removed, Resource{
+			Kind:      w.kind,
+			Namespace: w.meta.Namespace,
+			Name:      w.name,
+		})
+	}
+
+	return removed, nil
+}
+
+// SidecarConnection tunnels to the syncthing sidecar of a single pod. It
+// provides the same ports as NodeConnection, without radar.
+type SidecarConnection struct {
+	Namespace string
+	PodName   string
+
+	lock         sync.Mutex
+	service      *Service
+	tunnels      []*Tunnel
+	apiPort      int32
+	listenerPort int32
+	hooks        map[int]func(TunnelEvent)
+	nextHook     int
+}
+
+// NewSidecarConnection constructs a SidecarConnection for a pod.
+func NewSidecarConnection(namespace string, podName string) *SidecarConnection {
+	return &SidecarConnection{
+		Namespace: namespace,
+		PodName:   podName,
+		service:   NewService(),
+		hooks:     map[int]func(TunnelEvent){},
+	}
+}
+
+func (s *SidecarConnection) tunnel(name string, port int32) (int32, error) {
+	tun := NewTunnel(s.Namespace, s.PodName, port)
+	tun.name = name
+	tun.onChange = s.tunnelChanged
+
+	if err := tun.Start(); err != nil {
+		return 0, err
+	}
+
+	s.tunnels = append(s.tunnels, tun)
+
+	return tun.port(), nil
+}
+
+// Syncthing returns the local ports for the syncthing API and listener
+// tunnels, starting them if required.
+func (s *SidecarConnection) Syncthing() (int32, int32, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.apiPort != 0 {
+		return s.apiPort, s.listenerPort, nil
+	}
+
+	apiPort, err := s.tunnel(TunnelSyncthingAPI, s.service.SyncthingAPI)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	listenerPort, err := s.tunnel(
+		TunnelSyncthingListener, s.service.SyncthingListener)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	s.apiPort = apiPort
+	s.listenerPort = listenerPort
+
+	return apiPort, listenerPort, nil
+}
+
+// Host returns the host that the ports are on, the tunnels are local.
+func (s *SidecarConnection) Host() string {
+	return "127.0.0.1"
+}
+
+// OnChange adds a function that is called whenever a tunnel to the pod goes
+// down or comes back up. The returned function removes it again.
+func (s *SidecarConnection) OnChange(hook func(TunnelEvent)) func() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id := s.nextHook
+	s.nextHook++
+	s.hooks[id] = hook
+
+	return func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		delete(s.hooks, id)
+	}
+}
+
+func (s *SidecarConnection) tunnelChanged(event TunnelEvent) {
+	s.lock.Lock()
+	if event.Healthy && event.PortChanged() {
+		switch event.Name {
+		case TunnelSyncthingAPI:
+			s.apiPort = event.LocalPort
+		case TunnelSyncthingListener:
+			s.listenerPort = event.LocalPort
+		}
+	}
+
+	hooks := []func(TunnelEvent){}
+	for _, hook := range s.hooks {
+		hooks = append(hooks, hook)
+	}
+	s.lock.Unlock()
+
+	for _, hook := range hooks {
+		hook(event)
+	}
+}
+
+// Release closes the tunnels.
+func (s *SidecarConnection) Release() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, tun := range s.tunnels {
+		tun.Close()
+	}
+	s.tunnels = nil
+
+	return nil
+}
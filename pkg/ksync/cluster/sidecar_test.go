@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVolumeFor(t *testing.T) {
+	containers := []v1.Container{{
+		Name: "app",
+		VolumeMounts: []v1.VolumeMount{
+			{Name: "data", MountPath: "/data"},
+			{Name: "code", MountPath: "/data/code"},
+		},
+	}}
+
+	mount, err := volumeFor(containers, "app", "/data/code/src")
+	require.NoError(t, err)
+	assert.Equal(t, "code", mount.Name)
+
+	mount, err = volumeFor(containers, "", "/data")
+	require.NoError(t, err)
+	assert.Equal(t, "data", mount.Name)
+
+	// Only whole path segments count.
+	_, err = volumeFor(containers, "app", "/database")
+	assert.Error(t, err)
+
+	_, err = volumeFor(containers, "missing", "/data")
+	assert.Error(t, err)
+}
+
+func TestWorkloadSidecar(t *testing.T) {
+	w := &workload{
+		meta: &metav1.ObjectMeta{},
+		template: &v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "app"}},
+			},
+		},
+	}
+
+	mount := v1.VolumeMount{Name: "code", MountPath: "/code", ReadOnly: true}
+	assert.True(t, w.addSidecar(mount))
+	assert.False(t, w.addSidecar(mount))
+	require.Len(t, w.template.Spec.Containers, 2)
+
+	sidecar := w.template.Spec.Containers[1]
+	assert.Equal(t, SidecarName, sidecar.Name)
+	assert.False(t, sidecar.VolumeMounts[0].ReadOnly)
+
+	w.setSpecs([]string{"b", "a"})
+	assert.Equal(t, []string{"a", "b"}, w.specs())
+
+	w.setSpecs([]string{})
+	assert.Empty(t, w.specs())
+
+	w.removeSidecar()
+	assert.Len(t, w.template.Spec.Containers, 1)
+}
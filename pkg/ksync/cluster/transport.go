@@ -0,0 +1,226 @@
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// The ways to reach the ksync pod on a node. By default, everything goes
+// through port-forwards on the api server. The direct transports expose radar
+// and syncthing on the node itself, either on host ports of the DaemonSet's
+// pods or through a NodePort service.
+const (
+	TransportPortForward = "port-forward"
+	TransportHostPort    = "host-port"
+	TransportNodePort    = "node-port"
+)
+
+var (
+	// Transports are the supported ways to reach the ksync pods.
+	Transports = []string{
+		TransportPortForward,
+		TransportHostPort,
+		TransportNodePort,
+	}
+
+	// nodeProbeTimeout is how long to wait when checking whether a node can
+	// be reached directly.
+	nodeProbeTimeout = 3 * time.Second
+)
+
+// Transport returns the configured transport, the default is port-forward.
+func Transport() (string, error) {
+	transport := viper.GetString("transport")
+	if transport == "" {
+		return TransportPortForward, nil
+	}
+
+	for _, valid := range Transports {
+		if transport == valid {
+			return transport, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"unsupported transport %s, must be one of: %s",
+		transport,
+		strings.Join(Transports, ", "))
+}
+
+// NodeAddress returns the address to use when connecting to a node directly.
+// External addresses are preferred, falling back to the internal one for
+// clusters that do not have any (eg. local clusters).
+func (s *Service) NodeAddress(nodeName string) (string, error) {
+	node, err := Client.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	addresses := map[v1.NodeAddressType]string{}
+	for _, address := range node.Status.Addresses {
+		addresses[address.Type] = address.Address
+	}
+
+	for _, addressType := range []v1.NodeAddressType{
+		v1.NodeExternalIP, v1.NodeInternalIP} {
+
+		if address, ok := addresses[addressType]; ok {
+			return address, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s does not have an address", nodeName)
+}
+
+// nodePort looks up the port that a port of the ksync pod is exposed on for
+// the transport in use.
+func (s *Service) nodePort(transport string, port int32) (int32, error) {
+	if transport != TransportNodePort {
+		return port, nil
+	}
+
+	service, err := Client.CoreV1().Services(s.Namespace).Get(
+		s.name, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Port == port && servicePort.NodePort != 0 {
+			return servicePort.NodePort, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%s does not expose port %d", s.name, port)
+}
+
+// hostPorts exposes every port of a container on the node, when using the
+// host-port transport.
+func hostPorts(ports []v1.ContainerPort) []v1.ContainerPort {
+	if transport, _ := Transport(); transport != TransportHostPort {
+		return ports
+	}
+
+	for i := range ports {
+		ports[i].HostPort = ports[i].ContainerPort
+	}
+
+	return ports
+}
+
+// nodePortService exposes the ksync pods for the node-port transport. It only
+// routes to the ksync pod on the node that was connected to
+// (ExternalTrafficPolicy: Local), folders need to reach the pod on their
+// container's node.
+func (s *Service) nodePortService() *v1.Service {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      s.name,
+			Labels:    s.labels,
+		},
+		Spec: v1.ServiceSpec{
+			Type:                  v1.ServiceTypeNodePort,
+			Selector:              s.labels,
+			ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+			Ports: []v1.ServicePort{
+				{
+					Name:       "grpc",
+					Port:       s.RadarPort,
+					TargetPort: intstr.FromInt(int(s.RadarPort)),
+				},
+				{
+					Name:       "rest",
+					Port:       s.SyncthingAPI,
+					TargetPort: intstr.FromInt(int(s.SyncthingAPI)),
+				},
+				{
+					Name:       "sync",
+					Port:       s.SyncthingListener,
+					TargetPort: intstr.FromInt(int(s.SyncthingListener)),
+				},
+			},
+		},
+	}
+}
+
+func (s *Service) createNodePortService(upgrade bool) error {
+	service := s.nodePortService()
+
+	collection := Client.CoreV1().Services(s.Namespace)
+
+	existing, err := collection.Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		_, err = collection.Create(service)
+		return err
+	}
+
+	if upgrade {
+		// Updates have to keep the ports that have already been allocated.
+		service.ResourceVersion = existing.ResourceVersion
+		service.Spec.ClusterIP = existing.Spec.ClusterIP
+		for i := range service.Spec.Ports {
+			for _, port := range existing.Spec.Ports {
+				if port.Port == service.Spec.Ports[i].Port {
+					service.Spec.Ports[i].NodePort = port.NodePort
+				}
+			}
+		}
+
+		if _, err := collection.Update(service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsReachable checks whether radar on a node can be reached directly, using
+// the configured transport. It is always true for port-forwards, those go
+// through the api server.
+func (s *Service) IsReachable(nodeName string) error {
+	transport, err := Transport()
+	if err != nil {
+		return err
+	}
+
+	if transport == TransportPortForward {
+		return nil
+	}
+
+	address, err := s.NodeAddress(nodeName)
+	if err != nil {
+		return err
+	}
+
+	port, err := s.nodePort(transport, s.RadarPort)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout(
+		"tcp",
+		net.JoinHostPort(address, fmt.Sprintf("%d", port)),
+		nodeProbeTimeout)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
@@ -0,0 +1,44 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestTransport(t *testing.T) {
+	defer viper.Set("transport", "")
+
+	transport, err := Transport()
+	assert.NoError(t, err)
+	assert.Equal(t, TransportPortForward, transport)
+
+	viper.Set("transport", TransportNodePort)
+	transport, err = Transport()
+	assert.NoError(t, err)
+	assert.Equal(t, TransportNodePort, transport)
+
+	viper.Set("transport", "carrier-pigeon")
+	_, err = Transport()
+	assert.Error(t, err)
+}
+
+func TestHostPorts(t *testing.T) {
+	defer viper.Set("transport", "")
+
+	ports := func() []v1.ContainerPort {
+		return []v1.ContainerPort{{ContainerPort: 40321, Name: "grpc"}}
+	}
+
+	assert.Equal(t, int32(0), hostPorts(ports())[0].HostPort)
+
+	viper.Set("transport", TransportHostPort)
+	assert.Equal(t, int32(40321), hostPorts(ports())[0].HostPort)
+
+	// Host ports map straight through, there's nothing to look up.
+	port, err := NewService().nodePort(TransportHostPort, 8384)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(8384), port)
+}
@@ -6,7 +6,10 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/cenkalti/backoff"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/client-go/tools/portforward"
@@ -15,6 +18,34 @@ import (
 	"github.com/ksync/ksync/pkg/debug"
 )
 
+var (
+	// tunnelProbeTimeout is how long to wait when checking whether a tunnel is
+	// accepting connections.
+	tunnelProbeTimeout = 500 * time.Millisecond
+	// tunnelProbeInterval is how often running tunnels are checked.
+	tunnelProbeInterval = 10 * time.Second
+	// maxTunnelRetryInterval is the longest to wait between attempts to
+	// re-create a tunnel. Tunnels are retried until they are closed.
+	maxTunnelRetryInterval = 30 * time.Second
+
+	errTunnelClosed = fmt.Errorf("tunnel closed")
+)
+
+// TunnelEvent is emitted whenever a tunnel goes down or comes back up. When a
+// tunnel comes back on a different local port, PreviousPort is the port it
+// used to be on.
+type TunnelEvent struct {
+	Name         string
+	Healthy      bool
+	LocalPort    int32
+	PreviousPort int32
+}
+
+// PortChanged reports whether the tunnel has moved to another local port.
+func (e TunnelEvent) PortChanged() bool {
+	return e.LocalPort != e.PreviousPort
+}
+
 // Tunnel is the connection between the local host and a specific pod in the
 // remote cluster.
 type Tunnel struct {
@@ -23,19 +54,41 @@ type Tunnel struct {
 	PodName    string
 	Namespace  string
 	stopChan   chan struct{}
-	readyChan  chan struct{}
 	Out        *bytes.Buffer
+
+	name       string
+	lock       sync.Mutex
+	healthy    bool
+	forwarder  chan struct{}
+	resolvePod func() (string, error)
+	onChange   func(TunnelEvent)
 }
 
+// The local port and pod name change when the tunnel is re-created, they are
+// read under the lock.
 func (t *Tunnel) String() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
 	return debug.YamlString(t)
 }
 
 // Fields returns a set of structured fields for logging.
 func (t *Tunnel) Fields() log.Fields {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
 	return debug.StructFields(t)
 }
 
+// port returns the local port the tunnel is currently on.
+func (t *Tunnel) port() int32 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.LocalPort
+}
+
 // NewTunnel constructs a new tunnel for the namespace, pod and port.
 func NewTunnel(
 	namespace string,
@@ -47,7 +100,6 @@ func NewTunnel(
 		PodName:    podName,
 		Namespace:  namespace,
 		stopChan:   make(chan struct{}, 1),
-		readyChan:  make(chan struct{}, 1),
 		Out:        new(bytes.Buffer),
 	}
 }
@@ -59,8 +111,27 @@ func (t *Tunnel) Close() {
 	log.WithFields(t.Fields()).Debug("tunnel closed")
 }
 
-// Start starts a given tunnel connection
+// Start starts a given tunnel connection. Once running, the tunnel is
+// monitored and re-created whenever it goes down.
 func (t *Tunnel) Start() error {
+	local, err := getAvailablePort()
+	if err != nil {
+		return errors.Wrap(err, "could not find an available port")
+	}
+
+	forwardErr, err := t.forward(local)
+	if err != nil {
+		return err
+	}
+
+	go t.monitor(forwardErr)
+
+	return nil
+}
+
+// forward starts forwarding the local port, returning once it is ready. The
+// returned channel gets the forwarder's error whenever it stops.
+func (t *Tunnel) forward(localPort int32) (<-chan error, error) {
 	req := Client.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Namespace(t.Namespace).
@@ -69,49 +140,215 @@ func (t *Tunnel) Start() error {
 
 	transport, upgrader, err := spdy.RoundTripperFor(kubeCfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
-	if err != nil {
-		return err
-	}
 
-	local, err := getAvailablePort()
-	if err != nil {
-		return errors.Wrap(err, "could not find an available port")
-	}
-	t.LocalPort = local
+	t.lock.Lock()
+	t.LocalPort = localPort
+	t.lock.Unlock()
 
 	log.WithFields(debug.MergeFields(t.Fields(), log.Fields{
 		"url": req.URL(),
 	})).Debug("starting tunnel")
 
+	// Every forwarder closes its own ready channel.
+	stop := make(chan struct{})
+	ready := make(chan struct{})
 	pf, err := portforward.New(
 		dialer,
-		[]string{fmt.Sprintf("%d:%d", t.LocalPort, t.RemotePort)},
-		t.stopChan,
-		t.readyChan,
+		[]string{fmt.Sprintf("%d:%d", localPort, t.RemotePort)},
+		stop,
+		ready,
 		// TODO: there's better places to put this, really anywhere.
 		t.Out,
 		t.Out)
 
 	if err != nil {
-		return errors.Wrap(err, "unable to forward port")
+		return nil, errors.Wrap(err, "unable to forward port")
 	}
 
-	errChan := make(chan error)
+	errChan := make(chan error, 1)
 	go func() {
 		errChan <- pf.ForwardPorts()
 	}()
 
 	select {
 	case err = <-errChan:
-		return debug.ErrorOut("error forwarding ports", err, t)
+		return nil, debug.ErrorOut("error forwarding ports", err, t)
 	case <-pf.Ready:
 		log.WithFields(t.Fields()).Debug("tunnel running")
+	}
+
+	t.lock.Lock()
+	t.forwarder = stop
+	t.healthy = true
+	t.lock.Unlock()
+
+	return errChan, nil
+}
+
+func (t *Tunnel) stopForwarder() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.forwarder != nil {
+		close(t.forwarder)
+		t.forwarder = nil
+	}
+}
+
+// The forwarder returns when the connection to the api server goes away, but
+// nothing notices that on its own. The port is probed as well, in case the
+// forwarder gets stuck.
+func (t *Tunnel) monitor(forwardErr <-chan error) {
+	ticker := time.NewTicker(tunnelProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stopChan:
+			t.stopForwarder()
+			return
+
+		case err := <-forwardErr:
+			log.WithFields(t.Fields()).Debugf("tunnel stopped: %v", err)
+
+		case <-ticker.C:
+			if t.probe() {
+				continue
+			}
+
+			log.WithFields(t.Fields()).Debug("tunnel not accepting connections")
+		}
+
+		t.stopForwarder()
+
+		forwardErr = t.reconnect()
+		if forwardErr == nil {
+			return
+		}
+	}
+}
+
+// reconnect re-creates the tunnel until it works or the tunnel is closed. The
+// ksync pod might have been replaced, so its name is looked up again. The
+// same local port is reused when it is free, so that everything using the
+// tunnel can carry on.
+func (t *Tunnel) reconnect() <-chan error {
+	previous := t.port()
+	t.setHealthy(false, previous, previous)
+
+	retryBackoff := backoff.NewExponentialBackOff()
+	retryBackoff.MaxInterval = maxTunnelRetryInterval
+	retryBackoff.MaxElapsedTime = 0
+
+	var forwardErr <-chan error
+	connect := func() error {
+		select {
+		case <-t.stopChan:
+			return backoff.Permanent(errTunnelClosed)
+		default:
+		}
+
+		if t.resolvePod != nil {
+			podName, err := t.resolvePod()
+			if err != nil {
+				log.WithFields(t.Fields()).Debug(err)
+				return err
+			}
+			t.lock.Lock()
+			t.PodName = podName
+			t.lock.Unlock()
+		}
+
+		port := previous
+		if !portAvailable(port) {
+			available, err := getAvailablePort()
+			if err != nil {
+				return err
+			}
+			port = available
+		}
+
+		result, err := t.forward(port)
+		if err != nil {
+			log.WithFields(t.Fields()).Debug(err)
+			return err
+		}
+
+		forwardErr = result
+		return nil
+	}
+
+	if err := backoff.Retry(connect, retryBackoff); err != nil {
 		return nil
 	}
+
+	// Closed while the tunnel was being re-created.
+	select {
+	case <-t.stopChan:
+		t.stopForwarder()
+		return nil
+	default:
+	}
+
+	log.WithFields(t.Fields()).Info("tunnel re-created")
+	t.setHealthy(true, t.port(), previous)
+
+	return forwardErr
+}
+
+func (t *Tunnel) setHealthy(healthy bool, port, previous int32) {
+	t.lock.Lock()
+	t.healthy = healthy
+	onChange := t.onChange
+	t.lock.Unlock()
+
+	if onChange == nil {
+		return
+	}
+
+	onChange(TunnelEvent{
+		Name:         t.name,
+		Healthy:      healthy,
+		LocalPort:    port,
+		PreviousPort: previous,
+	})
+}
+
+// Healthy reports whether the tunnel is currently forwarding.
+func (t *Tunnel) Healthy() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.healthy
+}
+
+// probe checks whether the tunnel is accepting connections locally.
+func (t *Tunnel) probe() bool {
+	conn, err := net.DialTimeout(
+		"tcp", fmt.Sprintf("127.0.0.1:%d", t.port()), tunnelProbeTimeout)
+	if err != nil {
+		return false
+	}
+
+	conn.Close() // nolint: errcheck
+
+	return true
+}
+
+// #nosec
+func portAvailable(port int32) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+
+	l.Close() // nolint: errcheck
+
+	return true
 }
 
 // #nosec
@@ -0,0 +1,30 @@
+package cluster
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortAvailable(t *testing.T) {
+	port, err := getAvailablePort()
+	require.NoError(t, err)
+	assert.True(t, portAvailable(port))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close() // nolint: errcheck
+
+	taken := int32(l.Addr().(*net.TCPAddr).Port)
+	assert.False(t, portAvailable(taken))
+
+	// Something listening is what the probe looks for.
+	tun := NewTunnel("default", "pod", 80)
+	tun.LocalPort = taken
+	assert.True(t, tun.probe())
+
+	tun.LocalPort = port
+	assert.False(t, tun.probe())
+}
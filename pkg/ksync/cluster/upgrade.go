@@ -0,0 +1,474 @@
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/ksync/ksync/pkg/debug"
+)
+
+var (
+	// rolloutTimeout is how long an upgrade waits for every node to run the
+	// new version.
+	rolloutTimeout = 5 * time.Minute
+
+	// failedReasons are the reasons a container waits for that will not go away
+	// on their own, the rollout is failed straight away.
+	failedReasons = map[string]bool{
+		"CrashLoopBackOff":           true,
+		"ErrImagePull":               true,
+		"ImagePullBackOff":           true,
+		"InvalidImageName":           true,
+		"CreateContainerConfigError": true,
+	}
+
+	// daemonSetTolerated are the taints the DaemonSet controller tolerates on
+	// its own, the pods run on those nodes anyway.
+	daemonSetTolerated = map[string]bool{
+		"node.kubernetes.io/not-ready":       true,
+		"node.kubernetes.io/unreachable":     true,
+		"node.kubernetes.io/disk-pressure":   true,
+		"node.kubernetes.io/memory-pressure": true,
+		"node.kubernetes.io/pid-pressure":    true,
+		"node.kubernetes.io/unschedulable":   true,
+	}
+)
+
+// NodeRollout is the state of the ksync pod on a node during an upgrade. Pods
+// are updated when they run the DaemonSet's current revision.
+type NodeRollout struct {
+	NodeName string
+	PodName  string
+	Updated  bool
+	Ready    bool
+	Version  string
+	// Reason is why the pod is not ready, if it is not going to be.
+	Reason string
+}
+
+func (n NodeRollout) String() string {
+	switch {
+	case n.Reason != "":
+		return fmt.Sprintf("failed (%s)", n.Reason)
+	case n.PodName == "":
+		return "waiting for pod"
+	case !n.Updated:
+		return "waiting for update"
+	case !n.Ready:
+		return "starting"
+	case n.Version != "":
+		return fmt.Sprintf("ready (%s)", n.Version)
+	}
+
+	return "ready"
+}
+
+// Upgrade replaces the DaemonSet running on the cluster with the current one,
+// keeping what was there before so that it can be restored.
+type Upgrade struct {
+	service  *Service
+	previous *appsv1.DaemonSet
+
+	FromVersion string
+	ToVersion   string
+	// Changes describes how the DaemonSet's pods change.
+	Changes []string
+	Nodes   []NodeRollout
+}
+
+// NewUpgrade looks at what the cluster is running to upgrade it to
+// toVersion. Without a DaemonSet (on demand placement or nothing installed
+// yet), there is nothing to compare with or roll back to.
+func (s *Service) NewUpgrade(toVersion string) (*Upgrade, error) {
+	upgrade := &Upgrade{
+		service:   s,
+		ToVersion: toVersion,
+	}
+
+	if OnDemand() {
+		return upgrade, nil
+	}
+
+	previous, err := Client.AppsV1().DaemonSets(s.Namespace).Get(
+		s.name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return upgrade, nil
+		}
+		return nil, err
+	}
+	upgrade.previous = previous
+
+	// An unhealthy service is what's being upgraded sometimes, its version
+	// isn't required.
+	if version, err := s.Version(); err == nil {
+		upgrade.FromVersion = version.GitTag
+	} else {
+		log.WithFields(s.Fields()).Debugf("cannot get remote version: %v", err)
+	}
+
+	next, err := s.daemonSet()
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade.Changes = templateChanges(previous.Spec.Template, next.Spec.Template)
+
+	return upgrade, nil
+}
+
+// Required is false when the cluster already runs toVersion and nothing else
+// about the DaemonSet's pods would change.
+func (u *Upgrade) Required() bool {
+	return u.previous == nil ||
+		u.FromVersion != u.ToVersion ||
+		len(u.Changes) > 0
+}
+
+// Apply upgrades everything on the cluster, see Service.Run.
+func (u *Upgrade) Apply(withPSP bool) error {
+	return u.service.Run(true, withPSP)
+}
+
+// Wait waits for the rollout of the DaemonSet to finish, the same way
+// `kubectl rollout status` does. Nodes holds the state of each node the
+// DaemonSet runs on afterwards, either way.
+func (u *Upgrade) Wait() error {
+	if u.previous == nil {
+		return nil
+	}
+
+	rolloutBackoff := backoff.NewExponentialBackOff()
+	rolloutBackoff.MaxElapsedTime = rolloutTimeout
+
+	var nodes []NodeRollout
+	check := func() error {
+		daemonSet, current, err := u.service.rollout()
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		nodes = current
+
+		for _, node := range nodes {
+			if node.Reason != "" {
+				return backoff.Permanent(fmt.Errorf(
+					"ksync pod on %s failed: %s", node.NodeName, node.Reason))
+			}
+		}
+
+		return rolloutDone(daemonSet)
+	}
+
+	err := backoff.Retry(check, rolloutBackoff)
+
+	// Radar is only asked once everything is up, it should be running the new
+	// version everywhere.
+	if err == nil {
+		for i := range nodes {
+			if version, verErr := u.service.nodeVersion(
+				nodes[i].NodeName); verErr == nil {
+				nodes[i].Version = version.GitTag
+			}
+		}
+	}
+
+	u.Nodes = nodes
+
+	if err != nil {
+		return fmt.Errorf("rollout did not finish: %v", err)
+	}
+
+	return nil
+}
+
+// Rollback puts the DaemonSet's previous pod template back and waits for it
+// to roll out. Everything else (eg. service accounts) stays upgraded.
+func (u *Upgrade) Rollback() error {
+	if u.previous == nil {
+		return fmt.Errorf("there is no previous DaemonSet to roll back to")
+	}
+
+	collection := Client.AppsV1().DaemonSets(u.service.Namespace)
+
+	current, err := collection.Get(u.service.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	current.Spec.Template = u.previous.Spec.Template
+	if _, err := collection.Update(current); err != nil {
+		return err
+	}
+
+	log.WithFields(u.service.Fields()).Debug("rolled back DaemonSet")
+
+	return u.Wait()
+}
+
+// Summary describes the upgrade, one change per line.
+func (u *Upgrade) Summary() string {
+	from := u.FromVersion
+	if from == "" {
+		from = "unknown"
+	}
+
+	lines := []string{fmt.Sprintf("version: %s -> %s", from, u.ToVersion)}
+	lines = append(lines, u.Changes...)
+
+	return strings.Join(lines, "\n")
+}
+
+// rolloutDone is nil once the controller has seen the DaemonSet's latest
+// change and every node it is scheduled on runs an available, updated pod.
+func rolloutDone(daemonSet *appsv1.DaemonSet) error {
+	status := daemonSet.Status
+
+	if status.ObservedGeneration < daemonSet.Generation {
+		return fmt.Errorf("waiting for the DaemonSet to be updated")
+	}
+
+	if status.UpdatedNumberScheduled < status.DesiredNumberScheduled {
+		return fmt.Errorf("%d of %d nodes updated",
+			status.UpdatedNumberScheduled, status.DesiredNumberScheduled)
+	}
+
+	if status.NumberAvailable < status.DesiredNumberScheduled {
+		return fmt.Errorf("%d of %d updated pods available",
+			status.NumberAvailable, status.DesiredNumberScheduled)
+	}
+
+	return nil
+}
+
+// rollout returns the DaemonSet along with the state of every node it is
+// scheduled on, sorted by node name. Nodes without a pod yet are included.
+func (s *Service) rollout() (*appsv1.DaemonSet, []NodeRollout, error) {
+	daemonSet, err := Client.AppsV1().DaemonSets(s.Namespace).Get(
+		s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	revision, err := currentRevision(daemonSet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodeList, err := Client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := map[string]*NodeRollout{}
+	for _, node := range nodeList.Items {
+		if scheduledOn(daemonSet.Spec.Template, node) {
+			rows[node.Name] = &NodeRollout{NodeName: node.Name}
+		}
+	}
+
+	pods, err := Client.CoreV1().Pods(s.Namespace).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(s.labels).String(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Labels[agentLabel] != "" {
+			continue
+		}
+
+		row, ok := rows[pod.Spec.NodeName]
+		if !ok {
+			// Scheduled some way scheduledOn does not know about (eg. affinity).
+			row = &NodeRollout{NodeName: pod.Spec.NodeName}
+			rows[pod.Spec.NodeName] = row
+		}
+
+		// An outdated pod on the node is listed until it has been replaced.
+		updated := revision != "" &&
+			pod.Labels[appsv1.DefaultDaemonSetUniqueLabelKey] == revision
+		if row.PodName != "" && !updated {
+			continue
+		}
+
+		row.PodName = pod.Name
+		row.Updated = updated
+		row.Ready = podReady(pod)
+		row.Reason = podFailure(pod)
+	}
+
+	nodes := []NodeRollout{}
+	for _, row := range rows {
+		nodes = append(nodes, *row)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeName < nodes[j].NodeName
+	})
+
+	log.WithFields(debug.MergeFields(s.Fields(), log.Fields{
+		"generation": daemonSet.Generation,
+		"revision":   revision,
+		"nodes":      len(nodes),
+	})).Debug("checked rollout")
+
+	return daemonSet, nodes, nil
+}
+
+// currentRevision is the hash of the DaemonSet's newest revision, its pods
+// are labeled with the hash of the revision they were created from.
+func currentRevision(daemonSet *appsv1.DaemonSet) (string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(daemonSet.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+
+	revisions, err := Client.AppsV1().ControllerRevisions(
+		daemonSet.Namespace).List(metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var newest *appsv1.ControllerRevision
+	for i := range revisions.Items {
+		revision := &revisions.Items[i]
+		if newest == nil || revision.Revision > newest.Revision {
+			newest = revision
+		}
+	}
+
+	if newest == nil {
+		return "", nil
+	}
+
+	return newest.Labels[appsv1.DefaultDaemonSetUniqueLabelKey], nil
+}
+
+// scheduledOn is whether the DaemonSet controller runs template on a node.
+// Only the node selector and taints are taken into account.
+func scheduledOn(template v1.PodTemplateSpec, node v1.Node) bool {
+	if !labels.SelectorFromSet(template.Spec.NodeSelector).Matches(
+		labels.Set(node.Labels)) {
+		return false
+	}
+
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == v1.TaintEffectPreferNoSchedule ||
+			daemonSetTolerated[taint.Key] {
+			continue
+		}
+
+		tolerated := false
+		for j := range template.Spec.Tolerations {
+			if template.Spec.Tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+
+		if !tolerated {
+			return false
+		}
+	}
+
+	return true
+}
+
+func podReady(pod v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func podFailure(pod v1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil &&
+			failedReasons[status.State.Waiting.Reason] {
+
+			return fmt.Sprintf("%s: %s", status.Name, status.State.Waiting.Reason)
+		}
+	}
+
+	return ""
+}
+
+// templateChanges describes the differences between two of the DaemonSet's
+// pod templates that matter to users. The annotation forcing a restart on
+// every upgrade is ignored.
+func templateChanges(previous, next v1.PodTemplateSpec) []string {
+	changes := []string{}
+
+	containers := map[string]v1.Container{}
+	for _, container := range previous.Spec.Containers {
+		containers[container.Name] = container
+	}
+
+	for _, container := range next.Spec.Containers {
+		old, ok := containers[container.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s: added", container.Name))
+			continue
+		}
+		delete(containers, container.Name)
+
+		if old.Image != container.Image {
+			changes = append(changes, fmt.Sprintf(
+				"%s: image %s -> %s", container.Name, old.Image, container.Image))
+		}
+
+		if old.ImagePullPolicy != container.ImagePullPolicy {
+			changes = append(changes, fmt.Sprintf(
+				"%s: pull policy %s -> %s",
+				container.Name, old.ImagePullPolicy, container.ImagePullPolicy))
+		}
+
+		if !reflect.DeepEqual(old.Command, container.Command) {
+			changes = append(changes, fmt.Sprintf(
+				"%s: command changed", container.Name))
+		}
+
+		if !reflect.DeepEqual(old.Resources, container.Resources) {
+			changes = append(changes, fmt.Sprintf(
+				"%s: resources changed", container.Name))
+		}
+	}
+
+	for name := range containers {
+		changes = append(changes, fmt.Sprintf("%s: removed", name))
+	}
+
+	for _, field := range []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"volumes", previous.Spec.Volumes, next.Spec.Volumes},
+		{"node selector", previous.Spec.NodeSelector, next.Spec.NodeSelector},
+		{"tolerations", previous.Spec.Tolerations, next.Spec.Tolerations},
+		{"affinity", previous.Spec.Affinity, next.Spec.Affinity},
+		{"labels", previous.Labels, next.Labels},
+	} {
+		if !reflect.DeepEqual(field.old, field.new) {
+			changes = append(changes, fmt.Sprintf("%s changed", field.name))
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,144 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestTemplateChanges(t *testing.T) {
+	daemonSet, err := NewService().daemonSet()
+	require.NoError(t, err)
+
+	previous := *daemonSet.Spec.Template.DeepCopy()
+	next := *daemonSet.Spec.Template.DeepCopy()
+
+	next.Annotations["forceUpdate"] = "later"
+	assert.Empty(t, templateChanges(previous, next))
+
+	next.Spec.Containers[0].Image = "ksync/ksync:next"
+	next.Spec.Tolerations = []v1.Toleration{{Operator: v1.TolerationOpExists}}
+	assert.Equal(t, []string{
+		"ksync: image ksync/ksync -> ksync/ksync:next",
+		"tolerations changed",
+	}, templateChanges(previous, next))
+}
+
+func TestUpgradeSummary(t *testing.T) {
+	upgrade := &Upgrade{
+		previous:  &appsv1.DaemonSet{},
+		ToVersion: "0.5.0",
+	}
+	assert.True(t, upgrade.Required())
+	assert.Equal(t, "version: unknown -> 0.5.0", upgrade.Summary())
+
+	upgrade.FromVersion = "0.5.0"
+	assert.False(t, upgrade.Required())
+
+	upgrade.Changes = []string{"tolerations changed"}
+	assert.True(t, upgrade.Required())
+	assert.Equal(t,
+		"version: 0.5.0 -> 0.5.0\ntolerations changed", upgrade.Summary())
+}
+
+func TestNodeRollout(t *testing.T) {
+	pod := v1.Pod{
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name: "ksync",
+				State: v1.ContainerState{
+					Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+				},
+			}},
+		},
+	}
+	assert.Equal(t, "ksync: ImagePullBackOff", podFailure(pod))
+	assert.False(t, podReady(pod))
+
+	assert.Equal(t, "waiting for pod", NodeRollout{}.String())
+	assert.Equal(t, "waiting for update", NodeRollout{PodName: "ksync-abcde"}.String())
+	assert.Equal(t,
+		"ready (0.5.0)",
+		NodeRollout{
+			PodName: "ksync-abcde", Updated: true, Ready: true, Version: "0.5.0",
+		}.String())
+}
+
+func TestRollout(t *testing.T) {
+	viper.Set("daemonset-namespace", "kube-system")
+	service := NewService()
+
+	daemonSet, err := service.daemonSet()
+	require.NoError(t, err)
+	daemonSet.Generation = 2
+	daemonSet.Status = appsv1.DaemonSetStatus{
+		ObservedGeneration:     2,
+		DesiredNumberScheduled: 3,
+		UpdatedNumberScheduled: 1,
+		NumberAvailable:        1,
+	}
+
+	linux := map[string]string{"beta.kubernetes.io/os": "linux"}
+	node := func(name string, taints ...v1.Taint) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: objectMeta("", name, linux),
+			Spec:       v1.NodeSpec{Taints: taints},
+		}
+	}
+	revision := func(name string, number int64) *appsv1.ControllerRevision {
+		return &appsv1.ControllerRevision{
+			ObjectMeta: objectMeta("kube-system", name, merge(
+				map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: name},
+				ksyncLabels)),
+			Revision: number,
+		}
+	}
+	pod := func(name, nodeName, hash string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: objectMeta("kube-system", name, merge(
+				map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: hash},
+				ksyncLabels)),
+			Spec: v1.PodSpec{NodeName: nodeName},
+			Status: v1.PodStatus{Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue},
+			}},
+		}
+	}
+
+	_, restore := fakeCluster(
+		daemonSet,
+		revision("old", 1),
+		revision("new", 2),
+		node("node-1"),
+		node("node-2"),
+		node("node-3", v1.Taint{
+			Key: "node.kubernetes.io/not-ready", Effect: v1.TaintEffectNoExecute}),
+		node("gpu", v1.Taint{Key: "gpu", Effect: v1.TaintEffectNoSchedule}),
+		&v1.Node{ObjectMeta: objectMeta("", "windows", nil)},
+		pod("ksync-1", "node-1", "new"),
+		pod("ksync-2", "node-2", "old"),
+	)
+	defer restore()
+
+	current, nodes, err := service.rollout()
+	require.NoError(t, err)
+	assert.Equal(t, []NodeRollout{
+		{NodeName: "node-1", PodName: "ksync-1", Updated: true, Ready: true},
+		{NodeName: "node-2", PodName: "ksync-2", Ready: true},
+		{NodeName: "node-3"},
+	}, nodes)
+	assert.EqualError(t, rolloutDone(current), "1 of 3 nodes updated")
+
+	current.Status.UpdatedNumberScheduled = 3
+	assert.EqualError(t, rolloutDone(current), "1 of 3 updated pods available")
+
+	current.Status.NumberAvailable = 3
+	assert.NoError(t, rolloutDone(current))
+
+	current.Generation = 3
+	assert.Error(t, rolloutDone(current))
+}
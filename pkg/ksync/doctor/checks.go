@@ -1,6 +1,10 @@
 package doctor
 
 import (
+	"fmt"
+
+	"github.com/logrusorgru/aurora"
+
 	"github.com/ksync/ksync/pkg/cli"
 )
 
@@ -8,7 +12,10 @@ import (
 type Check struct {
 	Name string
 	Func func() error
-	Type string
+	// Detail is used instead of Func by checks that explain what they found,
+	// the explanation is shown when the check passes.
+	Detail func() (string, error)
+	Type   string
 }
 
 // CheckList is the full list of checks run by doctor.
@@ -42,11 +49,21 @@ var CheckList = []Check{
 		Func: HasClusterService,
 		Type: "post",
 	},
+	Check{
+		Name:   "Pod Security",
+		Detail: DescribePodSecurity,
+		Type:   "post",
+	},
 	Check{
 		Name: "Service Health",
 		Func: IsClusterServiceHealthy,
 		Type: "post",
 	},
+	Check{
+		Name: "Node Reachability",
+		Func: AreNodesReachable,
+		Type: "post",
+	},
 	Check{
 		Name: "Service Version",
 		Func: IsServiceCompatible,
@@ -58,22 +75,47 @@ var CheckList = []Check{
 		Type: "post",
 	},
 	Check{
-		Name: "Docker Storage Driver",
-		Func: IsDockerStorageCompatible,
+		Name: "Runtime Storage Driver",
+		Func: IsRuntimeStorageCompatible,
 		Type: "post",
 	},
 	Check{
-		Name: "Docker Storage Root",
-		Func: IsDockerGraphMatching,
+		Name: "Runtime Storage Root",
+		Func: IsRuntimeRootMatching,
 		Type: "post",
 	},
+	Check{
+		Name: "Leftover Resources",
+		Func: HasNoLeftovers,
+	},
 	Check{
 		Name: "Watch Running",
 		Func: IsWatchRunning,
 	},
+	Check{
+		Name: "Syncthing Restarts",
+		Func: IsSyncthingStable,
+	},
 }
 
 // Out provides pretty output with colors and spinners of progress.
 func (c *Check) Out() error {
-	return cli.TaskOut(c.Name, c.Func)
+	if c.Detail == nil {
+		return cli.TaskOut(c.Name, c.Func)
+	}
+
+	var detail string
+	if err := cli.TaskOut(c.Name, func() error {
+		var err error
+		detail, err = c.Detail()
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if detail != "" {
+		fmt.Printf("%s\t%s\n", aurora.Green("\u21b3"), detail)
+	}
+
+	return nil
 }
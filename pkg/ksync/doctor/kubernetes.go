@@ -1,6 +1,7 @@
 package doctor
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -17,7 +18,12 @@ var (
 	kubeConnectError     = `Unable to contact the cluster for context (%s). Does 'kubectl --context=%s cluster-info' work?`
 	kubeDaemonSetError   = `Unable to create the ksync daemonset in namespace (%s) for context (%s). You can test with 'kubectl --namespace=%s --context=%s auth can-i create daemonset'.`
 	kubePortforwardError = `Unable to setup port forwarding for the ksync pods in namespace (%s) for context (%s). You can test with 'kubectl --namespace=%s --context=%s auth can-i get pods --subresource=portforward'.`
+	kubePermissionError  = `Unable to %s %s in namespace (%s) for context (%s). You can test with 'kubectl --namespace=%s --context=%s auth can-i %s %s'.`
 	kubeVersionError     = `Your cluster version (%s) does not fall within the acceptible range: %s. Please upgrade to a compatible version.`
+
+	kubeScopeHint = `
+
+Without permissions for the whole cluster, install into your own namespace with '--scope=namespace --namespace=<name>'.`
 )
 
 // IsClusterVersionSupported verifies that the remote cluster's API version
@@ -73,7 +79,7 @@ func IsClusterConfigValid() error {
 // and return a basic result.
 func CanConnectToCluster() error {
 	ctx := viper.GetString("context")
-	client := cluster.Client.DiscoveryClient.RESTClient()
+	client := cluster.Client.Discovery().RESTClient()
 
 	if client.Get().Timeout(5*time.Second).Do().Error() != nil {
 		return fmt.Errorf(kubeConnectError, ctx, ctx)
@@ -103,8 +109,12 @@ func HasClusterPermissions() error {
 	if resp, err := reviews.Create(createReview); err != nil {
 		return err
 	} else if !resp.Status.Allowed {
-		return fmt.Errorf(
+		msg := fmt.Sprintf(
 			kubeDaemonSetError, service.Namespace, ctx, service.Namespace, ctx)
+		if scope, _ := cluster.Scope(); scope == cluster.ScopeCluster {
+			msg += kubeScopeHint
+		}
+		return errors.New(msg)
 	}
 
 	portforwardReview := &authorizationapi.SelfSubjectAccessReview{
@@ -125,5 +135,90 @@ func HasClusterPermissions() error {
 			kubePortforwardError, service.Namespace, ctx, service.Namespace, ctx)
 	}
 
+	if cluster.OnDemand() {
+		if err := hasAgentPermissions(service.Namespace); err != nil {
+			return err
+		}
+	}
+
+	if scope, _ := cluster.Scope(); scope == cluster.ScopeNamespace {
+		return hasNamespacePermissions(service.Namespace)
+	}
+
+	return nil
+}
+
+// hasAgentPermissions verifies that watch can start and remove agents.
+func hasAgentPermissions(namespace string) error {
+	return canAll(namespace, []authorizationapi.ResourceAttributes{
+		{Verb: "create", Resource: "pods"},
+		{Verb: "patch", Resource: "pods"},
+		{Verb: "delete", Resource: "pods"},
+	})
+}
+
+// hasNamespacePermissions verifies that everything else an install scoped to
+// a namespace creates can be created. Roles are only needed to grant a
+// PodSecurityPolicy.
+func hasNamespacePermissions(namespace string) error {
+	resources := []authorizationapi.ResourceAttributes{
+		{Resource: "serviceaccounts"},
+		{Resource: "secrets"},
+	}
+
+	withPSP, err := cluster.PSPSupported()
+	if err != nil {
+		return err
+	}
+
+	if withPSP {
+		resources = append(resources,
+			authorizationapi.ResourceAttributes{
+				Group: "rbac.authorization.k8s.io", Resource: "roles"},
+			authorizationapi.ResourceAttributes{
+				Group: "rbac.authorization.k8s.io", Resource: "rolebindings"})
+	}
+
+	for i := range resources {
+		resources[i].Verb = "create"
+	}
+
+	return canAll(namespace, resources)
+}
+
+// canAll verifies that the current context/user is allowed everything in
+// resources, in namespace.
+func canAll(
+	namespace string, resources []authorizationapi.ResourceAttributes) error {
+
+	ctx := viper.GetString("context")
+	reviews := cluster.Client.AuthorizationV1().SelfSubjectAccessReviews()
+
+	for _, attributes := range resources {
+		attributes := attributes
+		attributes.Namespace = namespace
+
+		review := &authorizationapi.SelfSubjectAccessReview{
+			Spec: authorizationapi.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &attributes,
+			},
+		}
+
+		if resp, err := reviews.Create(review); err != nil {
+			return err
+		} else if !resp.Status.Allowed {
+			return fmt.Errorf(
+				kubePermissionError,
+				attributes.Verb,
+				attributes.Resource,
+				namespace,
+				ctx,
+				namespace,
+				ctx,
+				attributes.Verb,
+				attributes.Resource)
+		}
+	}
+
 	return nil
 }
@@ -19,6 +19,16 @@ var (
 	errWatchNotRunning     = fmt.Errorf(`It appears that watch isn't running. You can start it with 'ksync watch'`)
 	errWatchNotResponding  = fmt.Errorf(`It appears that watch isn't responding`)
 	errSyncthingNotRunning = fmt.Errorf(`It appears that watch isn't running correctly. Please restart 'ksync watch' to correct this`)
+
+	syncthingRestartsError = `The local syncthing has exited and been restarted %d time(s), the last exit was: %s. Run 'ksync watch' with '--log-level=debug' to see why.`
+)
+
+var (
+	// unstableRestarts is how many restarts of the local syncthing there have to
+	// be before it is considered unstable.
+	unstableRestarts = int32(3)
+	// recentRestart is how long a single restart is reported for.
+	recentRestart = 10 * time.Minute
 )
 
 // DoesSyncthingExist verifies that the local binary exists.
@@ -74,3 +84,39 @@ func IsWatchRunning() error {
 
 	return nil
 }
+
+// IsSyncthingStable checks how often watch has had to restart the local
+// syncthing after it exited. A single restart a while ago is fine. When watch
+// isn't running there is nothing to check, "Watch Running" reports that.
+func IsSyncthingStable() error {
+	withTimeout, _ := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+
+	conn, err := grpc.DialContext(
+		withTimeout,
+		fmt.Sprintf("127.0.0.1:%d", viper.GetInt("port")),
+		[]grpc.DialOption{
+			grpc.WithBlock(),
+			grpc.WithInsecure(),
+		}...)
+	if err != nil {
+		log.Debug(err)
+		return nil
+	}
+	defer conn.Close() // nolint: errcheck
+
+	alive, err := pb.NewKsyncClient(conn).IsAlive(
+		context.Background(), &empty.Empty{})
+	if err != nil {
+		log.Debug(err)
+		return errWatchNotResponding
+	}
+
+	recent := alive.LastRestart > 0 &&
+		time.Since(time.Unix(alive.LastRestart, 0)) < recentRestart
+
+	if alive.Restarts >= unstableRestarts || recent {
+		return fmt.Errorf(syncthingRestartsError, alive.Restarts, alive.LastExit)
+	}
+
+	return nil
+}
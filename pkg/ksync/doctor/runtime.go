@@ -0,0 +1,137 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+
+	"github.com/ksync/ksync/pkg/ksync/cluster"
+	pb "github.com/ksync/ksync/pkg/proto"
+)
+
+var (
+	runtimeSupportError  = `The container runtime (%s) on node (%s) is not part of the supported list: %s. Please open an issue to add support for your container runtime.`
+	runtimeStorageError  = `The configured %s storage driver (%s) on node (%s) is not part of the supported list: %s. Please open an issue to add support for your storage driver.`
+	runtimeNoDriverError = `The %s runtime on node (%s) did not report a storage driver. Please check that the runtime is configured correctly.`
+	runtimeRootError     = `The configured %s storage root (%s) on node (%s) does not match the storage root specified: %s. Please check your remote storage root or pass the correct root in init with --%s.`
+
+	// runtimeRootFlags is the flag which configures the storage root for each
+	// container runtime.
+	runtimeRootFlags = map[string]string{
+		"docker":     "docker-root",
+		"containerd": "containerd-root",
+		"cri-o":      "crio-root",
+	}
+)
+
+// supportedList returns the keys of a support map as a sorted, comma separated
+// list for use in errors.
+func supportedList(m interface{}) string {
+	keys := []string{}
+	switch typed := m.(type) {
+	case map[string]bool:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]map[string]bool:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ", ")
+}
+
+// runtimeInfo fetches the storage configuration from radar for the runtime
+// running on the node.
+func runtimeInfo(service *cluster.Service, node string) (*pb.RuntimeInfo, error) {
+	runtime, err := service.Runtime(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := RuntimeDrivers[runtime]; !ok {
+		return nil, fmt.Errorf(
+			runtimeSupportError,
+			runtime,
+			node,
+			supportedList(RuntimeDrivers))
+	}
+
+	conn, err := cluster.NewConnection(node).Radar()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close() // nolint: errcheck
+
+	return pb.NewRadarClient(conn).GetRuntimeInfo(
+		context.Background(), &pb.Runtime{Name: runtime})
+}
+
+// IsRuntimeStorageCompatible verifies that the remote cluster has been
+// configured to use compatible container runtimes and storage drivers.
+func IsRuntimeStorageCompatible() error {
+	service := cluster.NewService()
+
+	nodes, err := service.NodeNames()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		info, err := runtimeInfo(service, node)
+		if err != nil {
+			return err
+		}
+
+		if info.Driver == "" {
+			return fmt.Errorf(runtimeNoDriverError, info.Name, node)
+		}
+
+		if drivers := RuntimeDrivers[info.Name]; !drivers[info.Driver] {
+			return fmt.Errorf(
+				runtimeStorageError,
+				info.Name,
+				info.Driver,
+				node,
+				supportedList(drivers))
+		}
+	}
+
+	return nil
+}
+
+// IsRuntimeRootMatching checks to see if the configured storage root for each
+// node's container runtime matches what's configured in radar.
+func IsRuntimeRootMatching() error {
+	service := cluster.NewService()
+
+	nodes, err := service.NodeNames()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		info, err := runtimeInfo(service, node)
+		if err != nil {
+			return err
+		}
+
+		flag := runtimeRootFlags[info.Name]
+		if viper.GetString(flag) != info.Root {
+			return fmt.Errorf(
+				runtimeRootError,
+				info.Name,
+				info.Root,
+				node,
+				viper.GetString(flag),
+				flag)
+		}
+	}
+
+	return nil
+}
@@ -16,6 +16,10 @@ var (
 - If you just ran init, wait a little longer and try again.
 - Run 'kubectl --namespace=%s --context=%s get pods -lapp=ksync' to look at what's going on.`
 
+	leftoverResourcesError = `Found resources from an older install of ksync:
+%s
+Run 'ksync clean --remote' to remove everything and 'ksync init' to install again.`
+
 	versionMismatch = `There is a mismatch between the local version (%s) and the cluster (%s).
 
 Run 'ksync init --upgrade' to fix.`
@@ -35,6 +39,33 @@ func HasClusterService() error {
 	return nil
 }
 
+// HasNoLeftovers verifies that there is nothing left on the cluster from older
+// installs, eg. in another namespace.
+func HasNoLeftovers() error {
+	leftovers, err := cluster.NewService().Leftovers()
+	if err != nil {
+		return err
+	}
+
+	if len(leftovers) == 0 {
+		return nil
+	}
+
+	list := ""
+	for _, resource := range leftovers {
+		list += fmt.Sprintf("- %s\n", resource)
+	}
+
+	return fmt.Errorf(leftoverResourcesError, list)
+}
+
+// DescribePodSecurity explains how the cluster service is admitted, either
+// with a PodSecurityPolicy or Pod Security Admission. It fails when Pod
+// Security Admission would reject the service's pods.
+func DescribePodSecurity() (string, error) {
+	return cluster.NewService().PodSecurity()
+}
+
 // IsClusterServiceHealthy verifies that the cluster service is healthy
 // across all nodes.
 func IsClusterServiceHealthy() error {
@@ -52,6 +83,10 @@ func IsClusterServiceHealthy() error {
 	if err != nil {
 		return err
 	} else if len(nodes) == 0 {
+		// Agents are only started once something is synced.
+		if cluster.OnDemand() {
+			return nil
+		}
 		return unhealthyError
 	}
 
@@ -69,7 +104,21 @@ func IsClusterServiceHealthy() error {
 // IsServiceCompatible verifies that the remote service is compatible with
 // the local client.
 func IsServiceCompatible() error {
-	version, err := cluster.NewService().Version()
+	service := cluster.NewService()
+
+	if cluster.OnDemand() {
+		nodes, err := service.NodeNames()
+		if err != nil {
+			return err
+		}
+
+		// There is nothing running to compare with yet.
+		if len(nodes) == 0 {
+			return nil
+		}
+	}
+
+	version, err := service.Version()
 	if err != nil {
 		return err
 	}
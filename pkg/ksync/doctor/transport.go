@@ -0,0 +1,48 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/ksync/ksync/pkg/ksync/cluster"
+)
+
+var (
+	nodeUnreachableError = `Node (%s) cannot be reached directly using the %s transport: %v
+
+- Make sure the node's address is routable from this host and that firewalls allow the ksync ports.
+- Run init again with --transport=%s if direct connections are not possible.`
+)
+
+// AreNodesReachable verifies that every node running the cluster service can
+// be connected to with the configured transport. Port-forwards go through the
+// api server and do not need the nodes to be reachable.
+func AreNodesReachable() error {
+	transport, err := cluster.Transport()
+	if err != nil {
+		return err
+	}
+
+	if transport == cluster.TransportPortForward {
+		return nil
+	}
+
+	service := cluster.NewService()
+
+	nodes, err := service.NodeNames()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		if err := service.IsReachable(node); err != nil {
+			return fmt.Errorf(
+				nodeUnreachableError,
+				node,
+				transport,
+				err,
+				cluster.TransportPortForward)
+		}
+	}
+
+	return nil
+}
@@ -12,7 +12,34 @@ var (
 
 	// DockerDriver is all the compatible storage drivers.
 	DockerDriver = map[string]bool{
-		"overlay":  true,
-		"overlay2": true,
+		"overlay":        true,
+		"overlay2":       true,
+		"fuse-overlayfs": true,
+		"btrfs":          true,
+		"zfs":            true,
+		"devicemapper":   true,
+	}
+
+	// RuntimeDrivers is all the compatible storage drivers for each container
+	// runtime.
+	RuntimeDrivers = map[string]map[string]bool{
+		"docker": DockerDriver,
+		// Containerd and CRI-O both mount the root filesystem in a known place, so
+		// any snapshotter or storage driver that produces a real mount works.
+		"containerd": {
+			"overlayfs":      true,
+			"fuse-overlayfs": true,
+			"native":         true,
+			"btrfs":          true,
+			"zfs":            true,
+			"devmapper":      true,
+		},
+		"cri-o": {
+			"overlay":      true,
+			"btrfs":        true,
+			"zfs":          true,
+			"devicemapper": true,
+			"vfs":          true,
+		},
 	}
 )
@@ -0,0 +1,400 @@
+package ksync
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	canonicalPath "path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/ksync/ksync/pkg/debug"
+	"github.com/ksync/ksync/pkg/ksync/cluster"
+)
+
+// execDebounce is how long the local folder has to be quiet before changes
+// are pushed. Editors and builds tend to write several files at once.
+var execDebounce = 500 * time.Millisecond
+
+// ExecFolder pushes a local folder into a container with tar archives streamed
+// over exec, the same way `kubectl cp` does. Nothing has to be installed on
+// the cluster, but only local changes make it to the remote container.
+type ExecFolder struct {
+	SpecName        string
+	RemoteContainer *RemoteContainer
+	Namespace       string
+	Reload          bool
+	LocalPath       string
+	RemotePath      string
+
+	lock    sync.Mutex
+	status  ServiceStatus
+	watcher *fsnotify.Watcher
+	stop    chan bool
+}
+
+// NewExecFolder constructs an ExecFolder based off the provided Service.
+func NewExecFolder(service *Service) *ExecFolder {
+	return &ExecFolder{
+		SpecName:        service.SpecDetails.Name,
+		RemoteContainer: service.RemoteContainer,
+		Namespace:       service.SpecDetails.Namespace,
+		Reload:          service.SpecDetails.Reload,
+		LocalPath:       service.SpecDetails.LocalPath,
+		RemotePath:      service.SpecDetails.RemotePath,
+
+		stop: make(chan bool),
+	}
+}
+
+func (f *ExecFolder) String() string {
+	return debug.YamlString(f)
+}
+
+// Fields returns a set of structured fields for logging.
+func (f *ExecFolder) Fields() log.Fields {
+	return debug.StructFields(f)
+}
+
+// ShortFields returns a set of structured fields to show users that are
+// simplified.
+func (f *ExecFolder) ShortFields() log.Fields {
+	return log.Fields{
+		"pod":  f.RemoteContainer.PodName,
+		"spec": f.SpecName,
+	}
+}
+
+// State returns the current status of the folder.
+func (f *ExecFolder) State() ServiceStatus {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.status
+}
+
+func (f *ExecFolder) setState(status ServiceStatus) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.status = status
+}
+
+func (f *ExecFolder) exec(command []string, stdin io.Reader) error {
+	_, err := cluster.Exec(
+		f.Namespace,
+		f.RemoteContainer.PodName,
+		f.RemoteContainer.Name,
+		command,
+		stdin)
+	return err
+}
+
+// push copies paths (relative to LocalPath) into the remote container.
+// Directories are copied with everything in them.
+func (f *ExecFolder) push(paths []string) error {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTar(writer, f.LocalPath, paths)) // nolint: errcheck
+	}()
+
+	// The remote path is passed as $0, so that it doesn't need quoting.
+	err := f.exec([]string{
+		"sh", "-c", `mkdir -p "$0" && tar xf - -C "$0"`, f.RemotePath,
+	}, reader)
+	reader.Close() // nolint: errcheck
+
+	return err
+}
+
+// remove deletes paths (relative to LocalPath) from the remote container.
+func (f *ExecFolder) remove(paths []string) error {
+	command := []string{"rm", "-rf", "--"}
+	for _, path := range paths {
+		command = append(
+			command, canonicalPath.Join(f.RemotePath, filepath.ToSlash(path)))
+	}
+
+	return f.exec(command, nil)
+}
+
+// reload restarts the remote container, see cluster.RestartContainer. Either
+// way there is a new container afterwards, which gets everything pushed again
+// (see UpdatePath).
+func (f *ExecFolder) reload() {
+	log.WithFields(f.ShortFields()).Info("issuing reload")
+	f.setState(ServiceReloading)
+
+	if err := cluster.RestartContainer(
+		f.Namespace, f.RemoteContainer.PodName, f.RemoteContainer.Name); err != nil {
+		log.WithFields(f.RemoteContainer.Fields()).Debug(err)
+		return
+	}
+
+	log.WithFields(f.ShortFields()).Info("reloaded")
+}
+
+// sync pushes the paths that changed locally and removes the ones that are
+// gone.
+func (f *ExecFolder) sync(changed map[string]bool) error {
+	updated := []string{}
+	removed := []string{}
+	for path := range changed {
+		rel, err := filepath.Rel(f.LocalPath, path)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			removed = append(removed, rel)
+			continue
+		}
+		updated = append(updated, rel)
+	}
+	sort.Strings(updated)
+	sort.Strings(removed)
+
+	if len(removed) > 0 {
+		if err := f.remove(removed); err != nil {
+			return err
+		}
+	}
+
+	if len(updated) > 0 {
+		if err := f.push(updated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addWatches watches a directory and every directory below it, fsnotify is
+// not recursive.
+func (f *ExecFolder) addWatches(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		return watcher.Add(path)
+	})
+}
+
+func (f *ExecFolder) watch(watcher *fsnotify.Watcher) {
+	changed := map[string]bool{}
+	var flush <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := f.addWatches(watcher, event.Name); err != nil {
+						log.WithFields(f.Fields()).Debug(err)
+					}
+				}
+			}
+
+			changed[event.Name] = true
+			flush = time.After(execDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithFields(f.Fields()).Debug(err)
+
+		case <-flush:
+			flush = nil
+
+			log.WithFields(f.ShortFields()).Info("updating")
+			f.setState(ServiceUpdating)
+
+			if err := f.sync(changed); err != nil {
+				log.WithFields(f.ShortFields()).Warnf("unable to update: %v", err)
+				f.setState(ServiceError)
+				// Leave the changes around, they are retried with the next one.
+				continue
+			}
+			changed = map[string]bool{}
+
+			log.WithFields(f.ShortFields()).Info("update complete")
+			f.setState(ServiceWatching)
+
+			if f.Reload {
+				f.reload()
+			}
+
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// Run pushes everything in the local folder to the remote container and then
+// keeps pushing local changes as they happen.
+func (f *ExecFolder) Run() error {
+	f.setState(ServiceStarting)
+
+	if err := f.push([]string{"."}); err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := f.addWatches(watcher, f.LocalPath); err != nil {
+		watcher.Close() // nolint: errcheck
+		return err
+	}
+
+	f.lock.Lock()
+	f.watcher = watcher
+	f.lock.Unlock()
+
+	go f.watch(watcher)
+
+	f.setState(ServiceWatching)
+
+	return nil
+}
+
+// UpdatePath pushes everything again. A new container starts out with the
+// image's files, anything pushed to the previous one is gone.
+func (f *ExecFolder) UpdatePath() error {
+	f.lock.Lock()
+	running := f.watcher != nil
+	f.lock.Unlock()
+
+	if !running {
+		return fmt.Errorf("folder not running")
+	}
+
+	f.setState(ServiceStarting)
+
+	if err := f.push([]string{"."}); err != nil {
+		return err
+	}
+
+	log.WithFields(f.ShortFields()).Info("remote folder updated")
+
+	f.setState(ServiceWatching)
+
+	return nil
+}
+
+// Reapply does nothing, there is no configuration kept anywhere else.
+func (f *ExecFolder) Reapply() error {
+	return nil
+}
+
+// Stop stops watching the local folder. Stopping more than once does nothing.
+func (f *ExecFolder) Stop() error {
+	f.lock.Lock()
+	select {
+	case <-f.stop:
+		f.lock.Unlock()
+		return nil
+	default:
+	}
+
+	close(f.stop)
+	watcher := f.watcher
+	f.watcher = nil
+	f.lock.Unlock()
+
+	if watcher != nil {
+		if err := watcher.Close(); err != nil {
+			return err
+		}
+	}
+
+	log.WithFields(f.Fields()).Debug("stopped folder")
+	return nil
+}
+
+// writeTar writes paths, relative to root, as a tar archive. Directories are
+// written with everything in them.
+func writeTar(w io.Writer, root string, paths []string) error {
+	tw := tar.NewWriter(w)
+
+	for _, path := range paths {
+		if err := filepath.Walk(
+			filepath.Join(root, path),
+			func(file string, info os.FileInfo, err error) error {
+				if err != nil {
+					// Files can go away while they are being walked, they'll be
+					// removed with the next update.
+					if os.IsNotExist(err) {
+						return nil
+					}
+					return err
+				}
+
+				return writeTarEntry(tw, root, file, info)
+			}); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func writeTarEntry(tw *tar.Writer, root, file string, info os.FileInfo) error {
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return err
+	}
+
+	if rel == "." {
+		return nil
+	}
+
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(file); err != nil {
+			return err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(rel)
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	// #nosec
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close() // nolint: errcheck
+
+	_, err = io.Copy(tw, src)
+	return err
+}
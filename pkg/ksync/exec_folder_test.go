@@ -0,0 +1,86 @@
+package ksync
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTar(t *testing.T) {
+	root, err := ioutil.TempDir("", "ksync-tar")
+	require.NoError(t, err)
+	defer os.RemoveAll(root) // nolint: errcheck
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src", "pkg"), 0755))
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(root, "src", "pkg", "main.go"), []byte("package main"), 0644))
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(root, "README"), []byte("readme"), 0644))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTar(&buf, root, []string{"src", "missing"}))
+
+	contents := map[string]string{}
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		contents[header.Name] = string(data)
+	}
+
+	// Only what was asked for, relative to the root.
+	assert.Equal(t, map[string]string{
+		"src":             "",
+		"src/pkg":         "",
+		"src/pkg/main.go": "package main",
+	}, contents)
+}
+
+func TestSpecDetailsTransport(t *testing.T) {
+	details := &SpecDetails{}
+	assert.NoError(t, details.IsValid())
+	assert.Equal(t, SpecTransportSyncthing, details.SyncTransport())
+
+	details.Transport = SpecTransportExec
+	assert.NoError(t, details.IsValid())
+
+	// Exec only goes from local to remote.
+	details.RemoteReadOnly = true
+	assert.Error(t, details.IsValid())
+
+	details.Transport = "carrier-pigeon"
+	details.RemoteReadOnly = false
+	assert.Error(t, details.IsValid())
+}
+
+func TestExecFolderStop(t *testing.T) {
+	watcher, err := fsnotify.NewWatcher()
+	require.NoError(t, err)
+
+	folder := &ExecFolder{
+		RemoteContainer: &RemoteContainer{},
+		watcher:         watcher,
+		stop:            make(chan bool),
+	}
+	go folder.watch(watcher)
+
+	require.NoError(t, folder.Stop())
+	assert.NoError(t, folder.Stop())
+
+	// The watcher is gone with the first stop.
+	assert.EqualError(t, folder.UpdatePath(), "folder not running")
+}
@@ -2,9 +2,11 @@ package ksync
 
 import (
 	"fmt"
+	"net"
 	"os"
 	canonicalPath "path"
 	// "path/filepath"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -27,11 +29,42 @@ import (
 
 var tooSoonReset = 3 * time.Second
 
+var (
+	// remoteRestarters coalesce the syncthing restarts for each node, all the
+	// folders syncing with a node share the same remote syncthing.
+	remoteRestarters     = map[string]*syncthing.Restarter{}
+	remoteRestartersLock sync.Mutex
+)
+
+func remoteRestarter(nodeName string) *syncthing.Restarter {
+	remoteRestartersLock.Lock()
+	defer remoteRestartersLock.Unlock()
+
+	restarter, ok := remoteRestarters[nodeName]
+	if !ok {
+		restarter = syncthing.NewRestarter(syncthing.RestartWindow)
+		remoteRestarters[nodeName] = restarter
+	}
+
+	return restarter
+}
+
+// syncthingConnection provides the ports of the remote syncthing. It is either
+// shared with every folder on a node (the DaemonSet) or the sidecar of a
+// single pod.
+type syncthingConnection interface {
+	Syncthing() (int32, int32, error)
+	Host() string
+	OnChange(func(cluster.TunnelEvent)) func()
+	Release() error
+}
+
 // Folder is what controls the syncing between a local folder and a specific
 // container running in the remote cluster.
 type Folder struct { // nolint: maligned
 	SpecName        string
 	RemoteContainer *RemoteContainer
+	Namespace       string
 	Reload          bool
 	LocalPath       string
 	RemotePath      string
@@ -42,44 +75,71 @@ type Folder struct { // nolint: maligned
 
 	id string
 
+	// Sidecars run syncthing in the pod itself, there is no radar.
+	sidecar    bool
+	connection syncthingConnection
+	node       *cluster.NodeConnection
+
+	// lock guards what changes when a tunnel moves to another port, tunnel
+	// events arrive on the tunnel's goroutine.
+	lock         sync.Mutex
 	localServer  *syncthing.Server
 	remoteServer *syncthing.Server
-
-	connection  *cluster.Connection
-	radarConn   *grpc.ClientConn
-	radarClient pb.RadarClient
+	radarClient  pb.RadarClient
+	listenerPort int32
 
 	ksyncConn   *grpc.ClientConn
 	ksyncClient pb.KsyncClient
 
+	unwatchTunnels   func()
 	restartContainer chan bool
 	stop             chan bool
 }
 
+// folderID is the syncthing folder id used for a spec and pod, it is the same
+// on the local and remote servers.
+func folderID(specName, podName string) string {
+	return fmt.Sprintf("%s-%s", specName, podName)
+}
+
 // NewFolder constructs a Folder based off the provided Service.
 func NewFolder(service *Service) *Folder {
-	return &Folder{
+	folder := &Folder{
 		SpecName:        service.SpecDetails.Name,
 		RemoteContainer: service.RemoteContainer,
+		Namespace:       service.SpecDetails.Namespace,
 		Reload:          service.SpecDetails.Reload,
 		LocalPath:       service.SpecDetails.LocalPath,
 		RemotePath:      service.SpecDetails.RemotePath,
 		LocalReadOnly:   service.SpecDetails.LocalReadOnly,
 		RemoteReadOnly:  service.SpecDetails.RemoteReadOnly,
 
-		id: fmt.Sprintf("%s-%s",
-			service.SpecDetails.Name, service.RemoteContainer.PodName),
-
-		connection: cluster.NewConnection(service.RemoteContainer.NodeName),
+		id: folderID(service.SpecDetails.Name, service.RemoteContainer.PodName),
 
 		stop: make(chan bool),
 	}
+
+	if service.SpecDetails.SyncTransport() == SpecTransportSidecar {
+		folder.sidecar = true
+		folder.connection = cluster.NewSidecarConnection(
+			folder.Namespace, service.RemoteContainer.PodName)
+	} else {
+		folder.node = cluster.AcquireConnection(service.RemoteContainer.NodeName)
+		folder.connection = folder.node
+	}
+
+	return folder
 }
 
 func (f *Folder) String() string {
 	return debug.YamlString(f)
 }
 
+// State returns the current status of the folder.
+func (f *Folder) State() ServiceStatus {
+	return f.Status
+}
+
 func (f *Folder) initErrorHandler() {
 	// Setup the k8s runtime to fail on unreturnable error (instead of looping).
 	runtime.ErrorHandlers = append(runtime.ErrorHandlers, func(fromHandler error) {
@@ -106,11 +166,52 @@ func (f *Folder) ShortFields() log.Fields {
 	}
 }
 
-// Get the remote folder's path from radar.
+func (f *Folder) radar() pb.RadarClient {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.radarClient
+}
+
+func (f *Folder) local() *syncthing.Server {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.localServer
+}
+
+func (f *Folder) remote() *syncthing.Server {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.remoteServer
+}
+
+func (f *Folder) listener() int32 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.listenerPort
+}
+
+func (f *Folder) setListener(port int32) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.listenerPort = port
+}
+
+// Get the remote folder's path from radar. The sidecar mounts the volume at
+// the same path as the container.
 func (f *Folder) path() (string, error) {
-	path, err := f.radarClient.GetBasePath(
+	if f.sidecar {
+		return f.RemotePath, nil
+	}
+
+	path, err := f.radar().GetBasePath(
 		context.Background(), &pb.ContainerPath{
 			ContainerId: f.RemoteContainer.ID,
+			Runtime:     f.RemoteContainer.Runtime,
 		})
 	if err != nil {
 		return "", err
@@ -120,13 +221,14 @@ func (f *Folder) path() (string, error) {
 }
 
 func (f *Folder) initRadarClient() error {
-	conn, err := f.connection.Radar()
+	conn, err := f.node.Radar()
 	if err != nil {
 		return err
 	}
 
-	f.radarConn = conn
+	f.lock.Lock()
 	f.radarClient = pb.NewRadarClient(conn)
+	f.lock.Unlock()
 
 	return nil
 }
@@ -151,10 +253,18 @@ func (f *Folder) initKsyncClient() error {
 // mount will not be present. This restarts just the syncthing container on the
 // remote node to refresh the mount table. It will potentially interrupt any
 // other folders operating on that host, but syncthing will quickly reconnect
-// and startup again.
+// and startup again. Radar skips the restart on nodes where the mounts are
+// propagated into the syncthing container. Folders starting on the same node
+// at the same time share a single restart.
 func (f *Folder) refreshSyncthing() error {
-	if _, err := f.radarClient.RestartSyncthing(
-		context.Background(), &empty.Empty{}); err != nil {
+	restart := func() error {
+		_, err := f.radar().RestartSyncthing(
+			context.Background(), &empty.Empty{})
+		return err
+	}
+
+	if err := remoteRestarter(f.RemoteContainer.NodeName).Restart(
+		restart); err != nil {
 		return debug.ErrorLocation(err)
 	}
 
@@ -171,20 +281,67 @@ func (f *Folder) initServers(apiPort int32) error {
 		return err
 	}
 
+	f.lock.Lock()
 	f.localServer = localServer
+	f.lock.Unlock()
 
+	return f.initRemoteServer(apiPort)
+}
+
+func (f *Folder) initRemoteServer(apiPort int32) error {
 	remoteServer, err := syncthing.NewServer(
-		fmt.Sprintf("localhost:%d", apiPort),
+		net.JoinHostPort(f.connection.Host(), fmt.Sprintf("%d", apiPort)),
 		viper.GetString("apikey"))
 	if err != nil {
 		return err
 	}
 
+	f.lock.Lock()
 	f.remoteServer = remoteServer
+	f.lock.Unlock()
 
 	return nil
 }
 
+// Tunnels to the node are re-created when they go down, but might come back
+// on a different local port. Everything pointing at the old port is moved
+// over.
+func (f *Folder) tunnelChanged(event cluster.TunnelEvent) {
+	fields := f.ShortFields()
+	fields["tunnel"] = event.Name
+
+	if !event.Healthy {
+		log.WithFields(fields).Warn("lost tunnel to remote, reconnecting")
+		return
+	}
+
+	if !event.PortChanged() {
+		log.WithFields(fields).Info("tunnel to remote recovered")
+		return
+	}
+
+	var err error
+	switch event.Name {
+	case cluster.TunnelRadar:
+		err = f.initRadarClient()
+	case cluster.TunnelSyncthingAPI:
+		err = f.initRemoteServer(event.LocalPort)
+	case cluster.TunnelSyncthingListener:
+		f.setListener(event.LocalPort)
+		if err = f.setDevices(event.LocalPort); err == nil {
+			err = f.local().Update()
+		}
+	}
+
+	if err != nil {
+		log.WithFields(fields).Error(err)
+		return
+	}
+
+	fields["port"] = event.LocalPort
+	log.WithFields(fields).Info("tunnel to remote moved")
+}
+
 // Kick the remote container when a folder has successfully completed updating.
 // This is monitored from the local syncthing server.
 func (f *Folder) hotReload() error {
@@ -205,10 +362,7 @@ func (f *Folder) hotReload() error {
 				log.WithFields(f.ShortFields()).Info("issuing reload")
 				f.Status = ServiceReloading
 
-				if _, err := f.radarClient.Restart(
-					context.Background(), &pb.ContainerPath{
-						ContainerId: f.RemoteContainer.ID,
-					}); err != nil {
+				if err := f.restart(); err != nil {
 					log.WithFields(f.RemoteContainer.Fields()).Debug(err)
 					continue
 				}
@@ -226,10 +380,26 @@ func (f *Folder) hotReload() error {
 	return nil
 }
 
+// restart restarts the remote container. Without radar, see
+// cluster.RestartContainer. The sidecar's volume is kept when it restarts.
+func (f *Folder) restart() error {
+	if f.sidecar {
+		return cluster.RestartContainer(
+			f.Namespace, f.RemoteContainer.PodName, f.RemoteContainer.Name)
+	}
+
+	_, err := f.radar().Restart(
+		context.Background(), &pb.ContainerPath{
+			ContainerId: f.RemoteContainer.ID,
+			Runtime:     f.RemoteContainer.Runtime,
+		})
+	return err
+}
+
 // Pay attention to the events coming off the local syncthing server to update
 // state and reload the remote container if required.
 func (f *Folder) watchEvents() error {
-	stream, err := f.localServer.Events()
+	stream, err := f.local().Events()
 	if err != nil {
 		return err
 	}
@@ -262,64 +432,76 @@ func (f *Folder) watchEvents() error {
 }
 
 // Update both the local and remote syncthing servers with devices allowing
-// them to mutually connect (via. the local tunnel). None of the discovery
-// or hole punching options in syncthing are used. The configuration forces
-// connections *only* over the local tunnel.
+// them to mutually connect (via. the local tunnel, or the node itself with a
+// direct transport). None of the discovery or hole punching options in
+// syncthing are used. The configuration forces connections *only* over the
+// connection to the node.
 func (f *Folder) setDevices(listenerPort int32) error {
 	host, err := os.Hostname()
 	if err != nil {
 		return err
 	}
 
-	localDevice := config.NewDeviceConfiguration(f.localServer.ID, host)
+	localDevice := syncthing.NewDeviceConfiguration(f.local().ID, host)
 
-	remoteDevice := config.NewDeviceConfiguration(
-		f.remoteServer.ID, f.RemoteContainer.PodName)
+	remoteDevice := syncthing.NewDeviceConfiguration(
+		f.remote().ID, f.RemoteContainer.PodName)
 	remoteDevice.Addresses = []string{
-		fmt.Sprintf("tcp://127.0.0.1:%d", listenerPort),
+		fmt.Sprintf("tcp://%s",
+			net.JoinHostPort(f.connection.Host(), fmt.Sprintf("%d", listenerPort))),
 	}
 
-	if err := f.remoteServer.SetDevice(&localDevice); err != nil {
+	if err := f.remote().SetDevice(&localDevice); err != nil {
 		return err
 	}
 
-	if err := f.localServer.SetDevice(&remoteDevice); err != nil {
+	if err := f.local().SetDevice(&remoteDevice); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+func (f *Folder) remoteFolder() (*config.FolderConfiguration, error) {
+	remotePath, err := f.path()
+	if err != nil {
+		return nil, err
+	}
+
+	remoteFolder := syncthing.NewFolderConfiguration(
+		f.local().ID, f.id, f.SpecName, fs.FilesystemTypeBasic, remotePath)
+
+	if f.RemoteReadOnly {
+		remoteFolder.Type = config.FolderTypeSendOnly
+		remoteFolder.IgnoreDelete = true
+	}
+
+	return &remoteFolder, nil
+}
+
 // Update both the local and remote folder configuration for syncthing. Once
 // this is updated, the syncing will actually start (assuming the devices can
-// connect via. the local tunnel).
+// connect via. the local tunnel). The folders are labeled with the spec's name,
+// which is used to clean up after previous runs (see Syncthing.CleanState).
 func (f *Folder) setFolders() error {
-	localFolder := config.NewFolderConfiguration(
-		f.remoteServer.ID, f.id, f.id, fs.FilesystemTypeBasic, f.LocalPath)
+	localFolder := syncthing.NewFolderConfiguration(
+		f.remote().ID, f.id, f.SpecName, fs.FilesystemTypeBasic, f.LocalPath)
 
 	if f.LocalReadOnly {
 		localFolder.Type = config.FolderTypeSendOnly
 		localFolder.IgnoreDelete = true
 	}
 
-	remotePath, err := f.path()
+	remoteFolder, err := f.remoteFolder()
 	if err != nil {
 		return err
 	}
 
-	remoteFolder := config.NewFolderConfiguration(
-		f.localServer.ID, f.id, f.id, fs.FilesystemTypeBasic, remotePath)
-
-	if f.RemoteReadOnly {
-		remoteFolder.Type = config.FolderTypeSendOnly
-		remoteFolder.IgnoreDelete = true
-	}
-
-	if err := f.localServer.SetFolder(&localFolder); err != nil {
+	if err := f.local().SetFolder(&localFolder); err != nil {
 		return err
 	}
 
-	if err := f.remoteServer.SetFolder(&remoteFolder); err != nil {
+	if err := f.remote().SetFolder(remoteFolder); err != nil {
 		return err
 	}
 
@@ -327,6 +509,8 @@ func (f *Folder) setFolders() error {
 }
 
 func (f *Folder) beginSync(listenerPort int32) error {
+	f.setListener(listenerPort)
+
 	if err := f.setDevices(listenerPort); err != nil {
 		return err
 	}
@@ -335,11 +519,11 @@ func (f *Folder) beginSync(listenerPort int32) error {
 		return err
 	}
 
-	if err := f.remoteServer.Update(); err != nil {
+	if err := f.remote().Update(); err != nil {
 		return err
 	}
 
-	if err := f.localServer.Update(); err != nil {
+	if err := f.local().Update(); err != nil {
 		return err
 	}
 
@@ -347,7 +531,7 @@ func (f *Folder) beginSync(listenerPort int32) error {
 	aliveBackoff.MaxInterval = time.Minute * 1
 
 	aliveErr := func() error {
-		switch f.localServer.IsAlive() {
+		switch f.local().IsAlive() {
 		case false:
 			return fmt.Errorf("syncthing not alive")
 		case true:
@@ -361,26 +545,125 @@ func (f *Folder) beginSync(listenerPort int32) error {
 		return err
 	}
 
-	_, err := f.ksyncClient.RestartSyncthing(context.Background(), &empty.Empty{})
+	// Newer versions of syncthing apply folders and devices live, restarting
+	// is only needed when the local server says so. Restarting interrupts every
+	// other folder.
+	required, err := f.local().RestartRequired()
+	if err != nil {
+		return err
+	}
+
+	if !required {
+		return nil
+	}
+
+	_, err = f.ksyncClient.RestartSyncthing(context.Background(), &empty.Empty{})
 
 	return err
 }
 
+// Reapply configures the devices and folders again. It is used after the local
+// syncthing has been restarted, which might have lost configuration that had
+// not been saved yet.
+func (f *Folder) Reapply() error {
+	if f.local() == nil || f.remote() == nil {
+		return nil
+	}
+
+	if err := f.local().Refresh(); err != nil {
+		return err
+	}
+
+	if err := f.setDevices(f.listener()); err != nil {
+		return err
+	}
+
+	if err := f.setFolders(); err != nil {
+		return err
+	}
+
+	if err := f.remote().Update(); err != nil {
+		return err
+	}
+
+	return f.local().Update()
+}
+
+// UpdatePath moves the remote folder to the current container's path. The path
+// is resolved by radar again, as the container's root changes every time it
+// restarts. The local folder and the tunnels stay as they are.
+func (f *Folder) UpdatePath() error {
+	if f.remote() == nil {
+		return fmt.Errorf("folder not running")
+	}
+
+	// The sidecar keeps running with the same volume.
+	if f.sidecar {
+		return nil
+	}
+
+	f.Status = ServiceStarting
+
+	// The new container's root has to show up in the syncthing container's
+	// mount table first.
+	if err := f.refreshSyncthing(); err != nil {
+		return err
+	}
+
+	if err := f.remote().Refresh(); err != nil {
+		return err
+	}
+
+	remoteFolder, err := f.remoteFolder()
+	if err != nil {
+		return err
+	}
+
+	if err := f.remote().SetFolder(remoteFolder); err != nil {
+		return err
+	}
+
+	if err := f.remote().Update(); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"pod":  f.RemoteContainer.PodName,
+		"spec": f.SpecName,
+		"path": remoteFolder.Path,
+	}).Info("remote folder moved")
+
+	f.Status = ServiceWatching
+
+	return nil
+}
+
 // Run starts syncing the folder between the local host and the remote
 // container. It is expected that syncthing is already running locally (
 // normally started by Syncthing).
 func (f *Folder) Run() error {
 	f.Status = ServiceStarting
 
-	if err := f.initRadarClient(); err != nil {
-		return err
-	}
+	if f.sidecar {
+		if err := cluster.EnsureSidecar(
+			f.Namespace,
+			f.RemoteContainer.PodName,
+			f.RemoteContainer.Name,
+			f.RemotePath,
+			f.SpecName); err != nil {
+			return err
+		}
+	} else {
+		if err := f.initRadarClient(); err != nil {
+			return err
+		}
 
-	if err := f.initKsyncClient(); err != nil {
-		return err
+		if err := f.refreshSyncthing(); err != nil {
+			return err
+		}
 	}
 
-	if err := f.refreshSyncthing(); err != nil {
+	if err := f.initKsyncClient(); err != nil {
 		return err
 	}
 
@@ -404,42 +687,85 @@ func (f *Folder) Run() error {
 		return err
 	}
 
-	return f.beginSync(listenerPort)
+	if err := f.beginSync(listenerPort); err != nil {
+		return err
+	}
+
+	return f.watchTunnels(apiPort, listenerPort)
+}
+
+// watchTunnels follows the tunnels once the folder is syncing, there is
+// nothing to move before that. The tunnels might have moved while starting up,
+// those changes are caught up on here.
+func (f *Folder) watchTunnels(apiPort, listenerPort int32) error {
+	f.unwatchTunnels = f.connection.OnChange(f.tunnelChanged)
+
+	currentAPI, currentListener, err := f.connection.Syncthing()
+	if err != nil {
+		return err
+	}
+
+	for _, event := range []cluster.TunnelEvent{
+		{
+			Name:         cluster.TunnelSyncthingAPI,
+			Healthy:      true,
+			LocalPort:    currentAPI,
+			PreviousPort: apiPort,
+		},
+		{
+			Name:         cluster.TunnelSyncthingListener,
+			Healthy:      true,
+			LocalPort:    currentListener,
+			PreviousPort: listenerPort,
+		},
+	} {
+		if event.PortChanged() {
+			f.tunnelChanged(event)
+		}
+	}
+
+	return nil
 }
 
 // Stop cleans up everything running in the background. It removes the
 // folder configuration from syncthing on the local/remote servers and
-// destroys the tunnels to those servers (for this folder, as connections
-// are per-folder).
+// releases the connection to the node (the tunnels are shared with every
+// other folder on that node).
 func (f *Folder) Stop() error {
 	close(f.stop)
 	<-f.stop
 
+	// Everything is cleaned up even when the remote can't be reached anymore
+	// (the connection to the cluster was lost). The first error is returned.
+	errs := []error{}
+
 	// Leave the devices, there might be other syncs with those nodes. It
 	// shouldn't be a huge deal because the tunnel will be down unless active.
-	if f.localServer != nil {
-		f.localServer.Stop()
+	if local := f.local(); local != nil {
+		local.Stop()
 
-		f.localServer.RemoveFolder(f.id)
-		if err := f.localServer.Update(); err != nil {
-			return err
-		}
+		errs = append(errs,
+			local.RemoveFolder(f.id),
+			local.Update())
 	}
 
-	if f.remoteServer != nil {
-		f.remoteServer.RemoveFolder(f.id)
-
-		if err := f.remoteServer.Update(); err != nil {
-			return err
-		}
+	if f.remote() != nil {
+		errs = append(errs,
+			f.remote().RemoveFolder(f.id),
+			f.remote().Update())
 	}
 
-	if err := f.radarConn.Close(); err != nil {
-		return err
+	// The connection is shared with every other folder on the node, it is only
+	// closed once the last one lets go.
+	if f.unwatchTunnels != nil {
+		f.unwatchTunnels()
 	}
+	errs = append(errs, f.connection.Release())
 
-	if err := f.connection.Stop(); err != nil {
-		return err
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
 	}
 
 	log.WithFields(f.Fields()).Debug("stopped folder")
@@ -0,0 +1,242 @@
+package ksync
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/ksync/ksync/pkg/ksync/cluster"
+)
+
+// resyncPeriod is how often every pod is handed to the specs again, even if
+// nothing has changed. This picks up pods whose services failed to start.
+var resyncPeriod = 5 * time.Minute
+
+// maxInformerRetryInterval is the longest to wait before listing again after
+// the cluster could not be reached.
+var maxInformerRetryInterval = 30 * time.Second
+
+// podInformer tracks the pods for a namespace and selector. Specs that share
+// both are handed the same informer, so there is only a single watch against
+// the api server for them.
+type podInformer struct {
+	namespace string
+	selector  string
+
+	informer cache.SharedIndexInformer
+	stop     chan struct{}
+
+	lock         sync.Mutex
+	specs        map[*Spec]bool
+	disconnected bool
+	retry        *backoff.ExponentialBackOff
+}
+
+var (
+	podInformers     = map[string]*podInformer{}
+	podInformersLock sync.Mutex
+)
+
+func podInformerKey(spec *Spec) string {
+	return fmt.Sprintf("%s/%s",
+		spec.Details.Namespace, strings.Join(spec.Details.Selector, ","))
+}
+
+func newPodInformer(namespace, selector string) *podInformer {
+	retry := backoff.NewExponentialBackOff()
+	retry.MaxInterval = maxInformerRetryInterval
+	retry.MaxElapsedTime = 0
+
+	p := &podInformer{
+		namespace: namespace,
+		selector:  selector,
+		stop:      make(chan struct{}),
+		specs:     map[*Spec]bool{},
+		retry:     retry,
+	}
+
+	p.informer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc:  p.list,
+			WatchFunc: p.watch,
+		},
+		&v1.Pod{},
+		resyncPeriod,
+		cache.Indexers{})
+
+	return p
+}
+
+// The reflector lists again whenever the watch breaks. Whether that works or not
+// is how a lost (and regained) connection to the cluster is noticed.
+func (p *podInformer) list(opts metav1.ListOptions) (runtime.Object, error) {
+	opts.LabelSelector = p.selector
+	pods, err := cluster.Client.CoreV1().Pods(p.namespace).List(opts)
+
+	// Specs are notified without holding the lock, they take their own and may
+	// call back into the informer.
+	p.lock.Lock()
+	changed := p.disconnected != (err != nil)
+	p.disconnected = err != nil
+	if err == nil {
+		p.retry.Reset()
+	}
+	specs := make([]*Spec, 0, len(p.specs))
+	for spec := range p.specs {
+		specs = append(specs, spec)
+	}
+	p.lock.Unlock()
+
+	if err != nil {
+		if changed {
+			for _, spec := range specs {
+				spec.disconnect()
+			}
+		}
+
+		p.wait()
+		return nil, err
+	}
+
+	if changed {
+		for _, spec := range specs {
+			go spec.reconcile(pods.Items)
+		}
+	}
+
+	return pods, nil
+}
+
+func (p *podInformer) watch(opts metav1.ListOptions) (watch.Interface, error) {
+	opts.LabelSelector = p.selector
+	w, err := cluster.Client.CoreV1().Pods(p.namespace).Watch(opts)
+	if err != nil {
+		p.wait()
+	}
+
+	return w, err
+}
+
+// wait holds off the reflector after a failed list or watch. It would
+// otherwise try again every second, instead the delay grows with every
+// failure until a list works again.
+func (p *podInformer) wait() {
+	p.lock.Lock()
+	delay := p.retry.NextBackOff()
+	p.lock.Unlock()
+
+	log.WithFields(log.Fields{
+		"namespace": p.namespace,
+		"selector":  p.selector,
+		"delay":     delay,
+	}).Debug("retrying pod informer")
+
+	select {
+	case <-time.After(delay):
+	case <-p.stop:
+	}
+}
+
+// handler maps the informer's events onto the spec. Shared informers replay
+// everything they already know about to new handlers, so specs that join an
+// existing informer get the pods that are already running.
+func (p *podInformer) handler(spec *Spec) cache.ResourceEventHandler {
+	active := func() bool {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+
+		return p.specs[spec]
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*v1.Pod); ok && active() {
+				spec.handlePod(pod)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pod, ok := obj.(*v1.Pod); ok && active() {
+				spec.handlePod(pod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// Deletes that happened while disconnected only show up as tombstones.
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			if pod, ok := obj.(*v1.Pod); ok && active() {
+				spec.removePod(pod)
+			}
+		},
+	}
+}
+
+// watchPods adds spec to the informer for its namespace and selector, starting
+// the informer if it is the first.
+func watchPods(spec *Spec) {
+	podInformersLock.Lock()
+	defer podInformersLock.Unlock()
+
+	key := podInformerKey(spec)
+
+	p, ok := podInformers[key]
+	if !ok {
+		p = newPodInformer(
+			spec.Details.Namespace, strings.Join(spec.Details.Selector, ","))
+		podInformers[key] = p
+
+		go p.informer.Run(p.stop)
+
+		log.WithFields(log.Fields{
+			"namespace": p.namespace,
+			"selector":  p.selector,
+		}).Debug("started pod informer")
+	}
+
+	p.lock.Lock()
+	p.specs[spec] = true
+	p.lock.Unlock()
+
+	p.informer.AddEventHandlerWithResyncPeriod(p.handler(spec), resyncPeriod)
+}
+
+// unwatchPods removes spec from its informer. The informer is stopped once no
+// specs are using it.
+func unwatchPods(spec *Spec) {
+	podInformersLock.Lock()
+	defer podInformersLock.Unlock()
+
+	key := podInformerKey(spec)
+
+	p, ok := podInformers[key]
+	if !ok {
+		return
+	}
+
+	p.lock.Lock()
+	delete(p.specs, spec)
+	remaining := len(p.specs)
+	p.lock.Unlock()
+
+	if remaining > 0 {
+		return
+	}
+
+	close(p.stop)
+	delete(podInformers, key)
+
+	log.WithFields(log.Fields{
+		"namespace": p.namespace,
+		"selector":  p.selector,
+	}).Debug("stopped pod informer")
+}
@@ -0,0 +1,105 @@
+package ksync
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/ksync/ksync/pkg/ksync/cluster"
+)
+
+func TestPodInformerKey(t *testing.T) {
+	first := NewSpec(&SpecDetails{
+		Name:      "first",
+		Namespace: "default",
+		Selector:  []string{"app=foo"},
+	})
+	second := NewSpec(&SpecDetails{
+		Name:      "second",
+		Namespace: "default",
+		Selector:  []string{"app=foo"},
+	})
+	other := NewSpec(&SpecDetails{
+		Name:      "other",
+		Namespace: "other",
+		Selector:  []string{"app=foo"},
+	})
+
+	assert.Equal(t, podInformerKey(first), podInformerKey(second))
+	assert.NotEqual(t, podInformerKey(first), podInformerKey(other))
+}
+
+func TestPodInformerHandler(t *testing.T) {
+	spec := NewSpec(&SpecDetails{Name: "spec", Namespace: "default"})
+	p := newPodInformer("default", "")
+	handler := p.handler(spec)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}
+
+	// Specs that are not registered with the informer ignore events.
+	spec.Status = SpecDisconnected
+	handler.OnAdd(pod)
+	assert.Equal(t, SpecDisconnected, spec.Status)
+
+	p.specs[spec] = true
+
+	handler.OnAdd(pod)
+	assert.Equal(t, SpecWaiting, spec.Status)
+
+	spec.Status = SpecDisconnected
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/pod", Obj: pod})
+	assert.Equal(t, SpecWaiting, spec.Status)
+}
+
+func TestPodInformerRetry(t *testing.T) {
+	previous := cluster.Client
+	defer func() { cluster.Client = previous }()
+
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "pods",
+		func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, fmt.Errorf("unreachable")
+		})
+	cluster.Client = client
+
+	spec := NewSpec(&SpecDetails{Name: "spec", Namespace: "default"})
+	p := newPodInformer("default", "")
+	p.specs[spec] = true
+
+	p.retry.InitialInterval = 50 * time.Millisecond
+	p.retry.RandomizationFactor = 0
+	p.retry.Reset()
+
+	// Failures hold off the reflector.
+	started := time.Now()
+	_, err := p.list(metav1.ListOptions{})
+	assert.Error(t, err)
+	assert.True(t, time.Since(started) >= p.retry.InitialInterval)
+	assert.Equal(t, SpecDisconnected, spec.Status)
+
+	// Unless the informer has been stopped.
+	close(p.stop)
+	started = time.Now()
+	_, err = p.list(metav1.ListOptions{})
+	assert.Error(t, err)
+	assert.True(t, time.Since(started) < p.retry.InitialInterval)
+
+	// Working again starts over with the shortest delay.
+	client.ReactionChain = client.ReactionChain[1:]
+	_, err = p.list(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.False(t, p.disconnected)
+	assert.Equal(t, p.retry.InitialInterval, p.retry.NextBackOff())
+}
@@ -0,0 +1,184 @@
+package ksync
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ksync/ksync/pkg/cli"
+	"github.com/ksync/ksync/pkg/ksync/cluster"
+	"github.com/ksync/ksync/pkg/syncthing"
+)
+
+// localDeviceID reads the identity of the local syncthing from its
+// certificate, without requiring it to be running. Everything that has been
+// configured on the remote syncthing servers by this host is shared with that
+// device. If there is no certificate, this host has never synced anything.
+func localDeviceID() (protocol.DeviceID, bool, error) {
+	base := filepath.Join(cli.ConfigPath(), "syncthing")
+
+	cert, err := tls.LoadX509KeyPair(
+		filepath.Join(base, "cert.pem"), filepath.Join(base, "key.pem"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return protocol.EmptyDeviceID, false, nil
+		}
+
+		return protocol.EmptyDeviceID, false, err
+	}
+
+	return protocol.NewDeviceID(cert.Certificate[0]), true, nil
+}
+
+// liveFolders returns the ids of every folder that should exist right now,
+// one for each running pod that matches a spec.
+func liveFolders(list *SpecList) (map[string]bool, error) {
+	live := map[string]bool{}
+
+	for _, spec := range list.Items {
+		opts := metav1.ListOptions{}
+		opts.LabelSelector = strings.Join(spec.Details.Selector, ",")
+
+		pods, err := cluster.Client.CoreV1().Pods(
+			spec.Details.Namespace).List(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+				continue
+			}
+
+			live[folderID(spec.Details.Name, pod.Name)] = true
+		}
+	}
+
+	return live, nil
+}
+
+// ReconcileRemote removes the folders and devices that this host left behind
+// on the remote syncthing servers. That happens whenever watch does not get a
+// chance to stop folders (it was killed, the laptop went to sleep). Folders
+// shared with the local device that do not belong to a running pod of a spec
+// in list are removed. The local device is removed from nodes that no longer
+// have any of its folders. Folders and devices of other hosts are left alone.
+// Nodes that can't be reached do not stop the others from being reconciled,
+// their errors are returned together.
+func ReconcileRemote(list *SpecList) error {
+	local, ok, err := localDeviceID()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		log.Debug("no local syncthing identity, nothing to reconcile")
+		return nil
+	}
+
+	live, err := liveFolders(list)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := cluster.NewService().NodeNames()
+	if err != nil {
+		return err
+	}
+
+	failed := []string{}
+	for _, node := range nodes {
+		if err := reconcileNode(node, local, live); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", node, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf(
+			"unable to reconcile nodes:\n%s", strings.Join(failed, "\n"))
+	}
+
+	return nil
+}
+
+func sharedWith(folder config.FolderConfiguration, id protocol.DeviceID) bool {
+	for _, device := range folder.Devices {
+		if device.DeviceID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func reconcileNode(
+	node string, local protocol.DeviceID, live map[string]bool) error {
+
+	connection := cluster.AcquireConnection(node)
+	defer connection.Release() // nolint: errcheck
+
+	apiPort, _, err := connection.Syncthing()
+	if err != nil {
+		return err
+	}
+
+	server, err := syncthing.NewServer(
+		net.JoinHostPort(connection.Host(), fmt.Sprintf("%d", apiPort)),
+		viper.GetString("apikey"))
+	if err != nil {
+		return err
+	}
+
+	return reconcileServer(server, node, local, live)
+}
+
+func reconcileServer(server *syncthing.Server,
+	node string, local protocol.DeviceID, live map[string]bool) error {
+
+	inUse := false
+
+	folders := append([]config.FolderConfiguration{}, server.Config.Folders...)
+	for _, folder := range folders {
+		if !sharedWith(folder, local) {
+			continue
+		}
+
+		if live[folder.ID] {
+			inUse = true
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"node":   node,
+			"folder": folder.ID,
+			"path":   folder.Path,
+		}).Info("removing orphaned folder")
+
+		if err := server.RemoveFolder(folder.ID); err != nil {
+			return err
+		}
+	}
+
+	if !inUse && server.GetDevice(local) != nil {
+		log.WithFields(log.Fields{
+			"node":   node,
+			"device": local,
+		}).Info("removing orphaned device")
+
+		if err := server.RemoveDevice(local); err != nil {
+			return err
+		}
+	}
+
+	return server.Update()
+}
@@ -0,0 +1,102 @@
+package ksync
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+
+	"github.com/ksync/ksync/pkg/syncthing"
+)
+
+func TestSharedWith(t *testing.T) {
+	local := protocol.NewDeviceID([]byte("local"))
+	other := protocol.NewDeviceID([]byte("other"))
+
+	folder := syncthing.NewFolderConfiguration(
+		local, folderID("spec", "pod"), "spec", fs.FilesystemTypeBasic, "/tmp")
+
+	assert.Equal(t, "spec-pod", folder.ID)
+	assert.True(t, sharedWith(folder, local))
+	assert.False(t, sharedWith(folder, other))
+}
+
+// remoteServer serves cfg like a remote syncthing with per-object
+// configuration, recording the changes made to it.
+func remoteServer(
+	t *testing.T, cfg config.Configuration) (*syncthing.Server, *[]string) {
+
+	id := protocol.NewDeviceID([]byte("remote"))
+	requests := []string{}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				requests = append(
+					requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+			}
+
+			w.Header().Set("X-Syncthing-Id", id.String())
+			w.Header().Set("Content-Type", "application/json")
+
+			if r.URL.Path == "/rest/system/config" {
+				require.NoError(t, json.NewEncoder(w).Encode(cfg))
+				return
+			}
+
+			fmt.Fprint(w, "{}") // nolint: errcheck
+		}))
+	t.Cleanup(ts.Close)
+
+	server, err := syncthing.NewServer(strings.TrimPrefix(ts.URL, "http://"), "key")
+	require.NoError(t, err)
+
+	return server, &requests
+}
+
+func TestReconcileServer(t *testing.T) {
+	local := protocol.NewDeviceID([]byte("local"))
+	other := protocol.NewDeviceID([]byte("other"))
+
+	folder := func(device protocol.DeviceID, pod string) config.FolderConfiguration {
+		return syncthing.NewFolderConfiguration(
+			device, folderID("spec", pod), "spec", fs.FilesystemTypeBasic, "/app")
+	}
+
+	cfg := config.Configuration{
+		Folders: []config.FolderConfiguration{
+			folder(local, "live"),
+			folder(local, "dead"),
+			folder(other, "theirs"),
+		},
+		Devices: []config.DeviceConfiguration{
+			syncthing.NewDeviceConfiguration(local, "local"),
+			syncthing.NewDeviceConfiguration(other, "other"),
+		},
+	}
+
+	server, requests := remoteServer(t, cfg)
+	require.NoError(t, reconcileServer(
+		server, "node", local, map[string]bool{"spec-live": true}))
+	assert.Equal(t, []string{"DELETE /rest/config/folders/spec-dead"}, *requests)
+
+	// Without any live folders, the local device goes as well. Other hosts are
+	// left alone.
+	server, requests = remoteServer(t, cfg)
+	require.NoError(t, reconcileServer(server, "node", local, map[string]bool{}))
+	assert.ElementsMatch(t, []string{
+		"DELETE /rest/config/folders/spec-live",
+		"DELETE /rest/config/folders/spec-dead",
+		fmt.Sprintf("DELETE /rest/config/devices/%s", local),
+	}, *requests)
+	assert.NotNil(t, server.GetDevice(other))
+	assert.Nil(t, server.GetDevice(local))
+}
@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/ksync/ksync/pkg/debug"
+	"github.com/ksync/ksync/pkg/ksync/cluster"
 	pb "github.com/ksync/ksync/pkg/proto"
 )
 
@@ -20,6 +21,7 @@ type RemoteContainer struct {
 	Name     string
 	NodeName string
 	PodName  string
+	Runtime  string
 }
 
 // NewRemoteContainer builds a RemoteContainer from a pod.
@@ -43,11 +45,15 @@ func NewRemoteContainer(
 	// be converted to something closer to `IsNotRunning()`
 	// and `IsMissingContainer()`
 	if containerName == "" && len(pod.Status.ContainerStatuses) > 0 {
+		runtime, id := cluster.ContainerRuntime(
+			pod.Status.ContainerStatuses[0].ContainerID)
+
 		return &RemoteContainer{
-			pod.Status.ContainerStatuses[0].ContainerID[9:],
+			id,
 			pod.Status.ContainerStatuses[0].Name,
 			pod.Spec.NodeName,
-			pod.Name}, nil
+			pod.Name,
+			runtime}, nil
 	}
 
 	for _, status := range pod.Status.ContainerStatuses {
@@ -55,11 +61,14 @@ func NewRemoteContainer(
 			continue
 		}
 
+		runtime, id := cluster.ContainerRuntime(status.ContainerID)
+
 		return &RemoteContainer{
-			status.ContainerID[9:],
+			id,
 			status.Name,
 			pod.Spec.NodeName,
 			pod.Name,
+			runtime,
 		}, nil
 	}
 
@@ -96,6 +105,7 @@ func DeserializeRemoteContainer(c *pb.RemoteContainer) (*RemoteContainer, error)
 		Name:     c.GetContainerName(),
 		NodeName: c.NodeName,
 		PodName:  c.GetPodName(),
+		Runtime:  c.GetRuntime(),
 	}
 	return result, nil
 }
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"github.com/golang/protobuf/ptypes/empty"
+	"golang.org/x/net/context"
+
+	"github.com/ksync/ksync/pkg/ksync/cluster"
+	pb "github.com/ksync/ksync/pkg/proto"
+)
+
+// GetConnections returns the connections to every node that is being synced
+// with, along with the health of their tunnels.
+func (k *ksyncServer) GetConnections(
+	ctx context.Context, _ *empty.Empty) (*pb.ConnectionList, error) {
+
+	items := []*pb.NodeConnection{}
+
+	for _, conn := range cluster.Connections() {
+		tunnels := []*pb.Tunnel{}
+		for _, tun := range conn.Tunnels() {
+			tunnels = append(tunnels, &pb.Tunnel{
+				Name:       tun.Name,
+				LocalPort:  tun.LocalPort,
+				RemotePort: tun.RemotePort,
+				Healthy:    tun.Healthy,
+			})
+		}
+
+		items = append(items, &pb.NodeConnection{
+			NodeName: conn.NodeName,
+			Refs:     int32(conn.Refs()),
+			Tunnels:  tunnels,
+		})
+	}
+
+	return &pb.ConnectionList{Items: items}, nil
+}
@@ -20,14 +20,20 @@ import (
 type ksyncServer struct {
 	SpecList  *ksync.SpecList
 	Syncthing *syncthing.Server
+
+	restarter *syncthing.Restarter
+	process   *ksync.Syncthing
 }
 
 func withDuration(duration time.Duration) (key string, value interface{}) {
 	return "grpc.time_ns", duration.Nanoseconds()
 }
 
-// Listen starts the ksync server locally.
-func Listen(list *ksync.SpecList, bind string, port int) error {
+// Listen starts the ksync server locally. The local syncthing process is used
+// to report on its health.
+func Listen(
+	list *ksync.SpecList, process *ksync.Syncthing, bind string, port int) error {
+
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", bind, port))
 	if err != nil {
 		return err
@@ -51,6 +57,8 @@ func Listen(list *ksync.SpecList, bind string, port int) error {
 	server := &ksyncServer{
 		SpecList:  list,
 		Syncthing: syncthingServer,
+		restarter: syncthing.NewRestarter(syncthing.RestartWindow),
+		process:   process,
 	}
 
 	logrusEntry := log.NewEntry(log.StandardLogger())
@@ -2,7 +2,6 @@ package server
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	log "github.com/sirupsen/logrus"
@@ -11,60 +10,50 @@ import (
 	pb "github.com/ksync/ksync/pkg/proto"
 )
 
-func (k *ksyncServer) Restart(ctx context.Context, _ *empty.Empty) (*pb.Error, error) {
-	log.Warn("requested restart")
-	time := time.Second * 10
-	k.debounce(ctx, &empty.Empty{}, time)
-	return &pb.Error{Msg: ""}, nil
-}
-
+// RestartSyncthing restarts the local syncthing server. Every folder asks for a
+// restart once it has been configured, requests are coalesced so that starting
+// many folders at once only restarts syncthing a single time.
 func (k *ksyncServer) RestartSyncthing(ctx context.Context, _ *empty.Empty) (*pb.Error, error) {
 
 	if !k.Syncthing.IsAlive() {
 		return &pb.Error{Msg: "Syncthing does not appear to be running locally"}, fmt.Errorf("%s", "Syncthing does not appear to be running locally")
 	}
 
-	log.Debug("restarting local syncthing")
+	err := k.restarter.Restart(func() error {
+		log.Debug("restarting local syncthing")
+		return k.Syncthing.Restart()
+	})
 
-	return &pb.Error{Msg: ""}, k.Syncthing.Restart()
+	return &pb.Error{Msg: ""}, err
 }
 
+// IsAlive reports on the health of the local syncthing, including how often it
+// has been restarted by the supervisor.
 func (k *ksyncServer) IsAlive(ctx context.Context, _ *empty.Empty) (*pb.Alive, error) {
 	log.Debug(k.Syncthing)
+
+	restarts, lastExit, lastRestart := k.process.Restarts()
+
+	var restartedAt int64
+	if !lastRestart.IsZero() {
+		restartedAt = lastRestart.Unix()
+	}
+
 	switch k.Syncthing.IsAlive() {
 	case true:
-		return &pb.Alive{Alive: true}, nil
+		return &pb.Alive{
+			Alive:       true,
+			Restarts:    int32(restarts),
+			LastExit:    lastExit,
+			LastRestart: restartedAt,
+		}, nil
 	case false:
-		return &pb.Alive{Alive: false}, nil
+		return &pb.Alive{
+			Alive:       false,
+			Restarts:    int32(restarts),
+			LastExit:    lastExit,
+			LastRestart: restartedAt,
+		}, nil
 	}
 	return &pb.Alive{Alive: false}, fmt.Errorf("Error during liveness check") // nolint: staticcheck
 }
-
-func (k *ksyncServer) debounce(ctx context.Context, _ *empty.Empty, t time.Duration) {
-	log.Warn("checking debounce")
-	incoming := make(chan int)
-
-	go func() {
-		var r int
-
-		d := time.NewTimer(t)
-		d.Stop()
-
-		for {
-			select {
-			case r = <-incoming:
-				d.Reset(t)
-				log.Warnf("Got %v requests", r)
-			case <-d.C:
-				pbErr, err := k.RestartSyncthing(ctx, &empty.Empty{})
-				// TODO: This should pass errors on an error channel
-				if pbErr != nil {
-					log.Fatal(pbErr)
-				}
-				if err != nil {
-					log.Fatal(err)
-				}
-			}
-		}
-	}()
-}
@@ -2,13 +2,24 @@ package ksync
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/cenkalti/backoff"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/ksync/ksync/pkg/debug"
 	pb "github.com/ksync/ksync/pkg/proto"
 )
 
+var (
+	// maxServiceRetryInterval is the longest to wait between attempts to start
+	// a service. Services are retried until they are stopped.
+	maxServiceRetryInterval = time.Minute
+
+	errServiceStopped = fmt.Errorf("service stopped")
+)
+
 // ServiceStatus is the current status of a service.
 type ServiceStatus string
 
@@ -27,13 +38,34 @@ const (
 	ServiceError ServiceStatus = "error"
 )
 
+// syncer moves files between the local folder and a remote container. Folder
+// uses syncthing (on the node or in a sidecar) and ExecFolder streams tar
+// archives over exec.
+type syncer interface {
+	Run() error
+	Stop() error
+	UpdatePath() error
+	Reapply() error
+	State() ServiceStatus
+}
+
 // Service reflects a spec that will sync files between a local and remote
 // folder.
 type Service struct {
 	RemoteContainer *RemoteContainer
 	SpecDetails     *SpecDetails
 
-	folder *Folder
+	lock          sync.Mutex
+	folder        syncer
+	starting      bool
+	removed       bool
+	stop          chan bool
+	done          chan bool
+	attempts      int
+	lastError     string
+	lastErrorTime time.Time
+
+	startupDuration time.Duration
 }
 
 // NewService constructs a Service to sync files between a local and remote
@@ -74,10 +106,22 @@ func (s *Service) Message() (*pb.Service, error) {
 		return nil, err
 	}
 
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var lastErrorTime int64
+	if !s.lastErrorTime.IsZero() {
+		lastErrorTime = s.lastErrorTime.Unix()
+	}
+
 	return &pb.Service{
 		RemoteContainer: cntr,
 		SpecDetails:     details,
-		Status:          string(s.Status()),
+		Status:          string(s.status()),
+		LastError:       s.lastError,
+		LastErrorTime:   lastErrorTime,
+		Attempts:        int32(s.attempts),
+		StartupMs:       int64(s.startupDuration / time.Millisecond),
 	}, nil
 }
 
@@ -93,42 +137,281 @@ func DeserializeService(s *pb.Service) (*Service, error) {
 		return nil, err
 	}
 
+	var lastErrorTime time.Time
+	if s.GetLastErrorTime() != 0 {
+		lastErrorTime = time.Unix(s.GetLastErrorTime(), 0)
+	}
+
 	return &Service{
 		RemoteContainer: cntr,
 		SpecDetails:     details,
+		attempts:        int(s.GetAttempts()),
+		lastError:       s.GetLastError(),
+		lastErrorTime:   lastErrorTime,
+		startupDuration: time.Duration(s.GetStartupMs()) * time.Millisecond,
 	}, nil
 }
 
 // Status returns the current status of this service.
 func (s *Service) Status() ServiceStatus {
-	if s.folder == nil {
-		return ServiceStopped
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.status()
+}
+
+func (s *Service) status() ServiceStatus {
+	if s.folder != nil {
+		return s.folder.State()
+	}
+
+	// Between attempts, either waiting to try again or for a previous run to
+	// finish.
+	if s.stop != nil {
+		if s.lastError != "" {
+			return ServiceError
+		}
+
+		return ServiceStarting
 	}
 
-	return s.folder.Status
+	return ServiceStopped
 }
 
-// Start runs this service.
+// LastError returns why the last attempt to start this service failed, when
+// that happened and how many attempts there have been in total.
+func (s *Service) LastError() (string, time.Time, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.lastError, s.lastErrorTime, s.attempts
+}
+
+// Start runs this service. Starting happens in the background and is retried
+// with backoff until it works or the service is stopped. The reason for the
+// last failure is kept around to show users (see LastError).
 func (s *Service) Start() error {
-	if s.folder != nil {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.removed {
+		return errServiceStopped
+	}
+
+	if s.stop != nil {
 		return fmt.Errorf("already running")
 	}
 
-	s.folder = NewFolder(s)
+	// A previous run might still be busy stopping its folder, they share the
+	// same syncthing configuration.
+	previous := s.done
+
+	s.stop = make(chan bool)
+	s.done = make(chan bool)
+	go s.run(s.stop, s.done, previous)
+
+	return nil
+}
+
+func (s *Service) run(stop, done, previous chan bool) {
+	defer close(done)
+
+	if previous != nil {
+		<-previous
+	}
+
+	started := time.Now()
+
+	retryBackoff := backoff.NewExponentialBackOff()
+	retryBackoff.MaxInterval = maxServiceRetryInterval
+	retryBackoff.MaxElapsedTime = 0
+
+	if err := backoff.Retry(func() error {
+		return s.attempt(stop)
+	}, retryBackoff); err != nil {
+		log.WithFields(s.Fields()).Debug(err)
+		return
+	}
 
-	if err := s.folder.Run(); err != nil {
+	// This includes waiting for a startup slot and every failed attempt.
+	duration := time.Since(started)
+
+	s.lock.Lock()
+	s.startupDuration = duration
+	s.lock.Unlock()
+
+	fields := s.ShortFields()
+	fields["duration"] = duration.Round(time.Millisecond)
+	log.WithFields(fields).Info("folder sync running")
+}
+
+func (s *Service) newSyncer() syncer {
+	if s.SpecDetails.SyncTransport() == SpecTransportExec {
+		return NewExecFolder(s)
+	}
+
+	return NewFolder(s)
+}
+
+func (s *Service) attempt(stop chan bool) error {
+	release, ok := acquireStartup(stop)
+	if !ok {
+		return backoff.Permanent(errServiceStopped)
+	}
+	defer release()
+
+	s.lock.Lock()
+	select {
+	case <-stop:
+		s.lock.Unlock()
+		return backoff.Permanent(errServiceStopped)
+	default:
+	}
+
+	folder := s.newSyncer()
+	s.folder = folder
+	s.starting = true
+	s.attempts++
+	s.lock.Unlock()
+
+	err := folder.Run()
+
+	s.lock.Lock()
+	s.starting = false
+
+	stopped := false
+	select {
+	case <-stop:
+		stopped = true
+	default:
+	}
+
+	if err != nil || stopped {
+		s.folder = nil
+	}
+
+	if err != nil {
+		s.lastError = err.Error()
+		s.lastErrorTime = time.Now()
+	} else if !stopped {
+		// The service is watching, earlier failures no longer apply.
+		s.lastError = ""
+		s.lastErrorTime = time.Time{}
+	}
+	s.lock.Unlock()
+
+	// Stopping was left to this attempt as the folder was still starting.
+	if err != nil || stopped {
+		if stopErr := folder.Stop(); stopErr != nil {
+			log.WithFields(s.Fields()).Debug(stopErr)
+		}
+	}
+
+	if stopped {
+		return backoff.Permanent(errServiceStopped)
+	}
+
+	if err != nil {
+		log.WithFields(s.ShortFields()).Warnf("unable to start, retrying: %v", err)
+	}
+
+	return err
+}
+
+// Restart stops the service and starts it again, recreating the tunnels to the
+// remote cluster.
+func (s *Service) Restart() error {
+	if err := s.halt(); err != nil {
+		log.WithFields(s.Fields()).Debug(err)
+	}
+
+	// Specs restart services without holding their lock, the service might have
+	// been stopped for good in the meantime.
+	if err := s.Start(); err != errServiceStopped {
 		return err
 	}
 
-	log.WithFields(s.ShortFields()).Info("folder sync running")
+	return nil
+}
+
+// UpdateContainer points the service at a new container for the same pod and
+// container name, this happens whenever the container restarts. The remote
+// folder is moved to the new container's path in place. If that does not work
+// out, the whole service is restarted.
+func (s *Service) UpdateContainer(cntr *RemoteContainer) error {
+	log.WithFields(log.Fields{
+		"pod":  cntr.PodName,
+		"spec": s.SpecDetails.Name,
+		"old":  s.RemoteContainer.ID,
+		"new":  cntr.ID,
+	}).Info("container changed")
+
+	// The folder shares RemoteContainer, update it in place so that it picks up
+	// the new ID.
+	s.lock.Lock()
+	*s.RemoteContainer = *cntr
+	folder := s.folder
+	starting := s.starting
+	s.lock.Unlock()
+
+	// Services that are still starting pick up the new container with their
+	// next attempt.
+	if folder == nil || starting {
+		return nil
+	}
+
+	if err := folder.UpdatePath(); err != nil {
+		log.WithFields(s.Fields()).Debug(err)
+		return s.Restart()
+	}
 
 	return nil
 }
 
+// Reapply configures syncthing for this service again.
+func (s *Service) Reapply() error {
+	s.lock.Lock()
+	folder := s.folder
+	starting := s.starting
+	s.lock.Unlock()
+
+	if folder == nil || starting {
+		return nil
+	}
+
+	return folder.Reapply()
+}
+
 // Stop halts a service that has been running in the background.
 func (s *Service) Stop() error {
 	log.WithFields(s.ShortFields()).Info("stopping")
 
 	log.WithFields(s.Fields()).Debug("stopping service")
-	return s.folder.Stop()
+
+	s.lock.Lock()
+	s.removed = true
+	s.lock.Unlock()
+
+	return s.halt()
+}
+
+func (s *Service) halt() error {
+	s.lock.Lock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+
+	folder := s.folder
+	if s.starting {
+		folder = nil
+	}
+	s.folder = nil
+	s.lock.Unlock()
+
+	if folder == nil {
+		return nil
+	}
+
+	return folder.Stop()
 }
@@ -2,7 +2,6 @@ package ksync
 
 import (
 	"fmt"
-	"reflect"
 
 	log "github.com/sirupsen/logrus"
 	"k8s.io/api/core/v1"
@@ -70,14 +69,36 @@ func NewServiceList() *ServiceList {
 
 // Add takes a pod/spec, creates a new service, adds it to the list and starts it.
 func (s *ServiceList) Add(pod *v1.Pod, details *SpecDetails) error {
-	cntr, err := NewRemoteContainer(pod, details.ContainerName)
+	update, err := s.add(pod, details)
 	if err != nil {
 		return err
 	}
 
-	service := NewService(cntr, details)
-	if s.Has(service) {
-		return &errors.StatusError{
+	if update != nil {
+		return update()
+	}
+
+	return nil
+}
+
+// add is Add without the parts that talk to the cluster. New services start in
+// the background. When the pod's container was restarted, the existing service
+// has to follow it, which is returned for the caller to run once it no longer
+// holds any locks.
+func (s *ServiceList) add(pod *v1.Pod, details *SpecDetails) (func() error, error) {
+	cntr, err := NewRemoteContainer(pod, details.ContainerName)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing := s.find(cntr); existing != nil {
+		// The container was restarted (or replaced) and the folder needs to
+		// follow it. Otherwise, this is just another update for the same pod.
+		if cntr.ID != "" && existing.RemoteContainer.ID != cntr.ID {
+			return func() error { return existing.UpdateContainer(cntr) }, nil
+		}
+
+		return nil, &errors.StatusError{
 			ErrStatus: metav1.Status{
 				Status:  metav1.StatusFailure,
 				Reason:  metav1.StatusReasonAlreadyExists,
@@ -85,6 +106,7 @@ func (s *ServiceList) Add(pod *v1.Pod, details *SpecDetails) error {
 			}}
 	}
 
+	service := NewService(cntr, details)
 	s.Items = append(s.Items, service)
 
 	log.WithFields(log.Fields{
@@ -94,18 +116,26 @@ func (s *ServiceList) Add(pod *v1.Pod, details *SpecDetails) error {
 
 	log.WithFields(service.Fields()).Debug("added service")
 
-	return service.Start()
+	return nil, service.Start()
 }
 
-// Has checks for equivalence between the existing services.
-func (s *ServiceList) Has(target *Service) bool {
+// find returns the service for the same pod and container as cntr. The
+// container's ID is ignored, it changes every time the container restarts.
+func (s *ServiceList) find(cntr *RemoteContainer) *Service {
 	for _, service := range s.Items {
-		if reflect.DeepEqual(service.RemoteContainer, target.RemoteContainer) {
-			return true
+		if service.RemoteContainer.PodName == cntr.PodName &&
+			service.RemoteContainer.Name == cntr.Name {
+			return service
 		}
 	}
 
-	return false
+	return nil
+}
+
+// Has checks whether there is already a service for the target's pod and
+// container.
+func (s *ServiceList) Has(target *Service) bool {
+	return s.find(target.RemoteContainer) != nil
 }
 
 // Pop fetches a service by pod name and removes it from the list.
@@ -2,6 +2,8 @@ package ksync
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -11,3 +13,65 @@ func init() {
 func TestGetServices(t *testing.T) {
 
 }
+
+func TestServiceListHas(t *testing.T) {
+	details := &SpecDetails{Name: "spec"}
+	list := NewServiceList()
+	list.Items = append(list.Items, NewService(&RemoteContainer{
+		ID:      "old",
+		Name:    "app",
+		PodName: "pod",
+	}, details))
+
+	// A restarted container has a new ID, but is the same service.
+	assert.True(t, list.Has(NewService(&RemoteContainer{
+		ID:      "new",
+		Name:    "app",
+		PodName: "pod",
+	}, details)))
+
+	assert.False(t, list.Has(NewService(&RemoteContainer{
+		ID:      "old",
+		Name:    "sidecar",
+		PodName: "pod",
+	}, details)))
+
+	assert.False(t, list.Has(NewService(&RemoteContainer{
+		ID:      "old",
+		Name:    "app",
+		PodName: "other",
+	}, details)))
+}
+
+func TestServiceUpdateContainer(t *testing.T) {
+	cntr := &RemoteContainer{ID: "old", Name: "app", PodName: "pod"}
+	service := NewService(cntr, &SpecDetails{Name: "spec"})
+
+	assert.NoError(t, service.UpdateContainer(
+		&RemoteContainer{ID: "new", Name: "app", PodName: "pod"}))
+
+	// The container is shared with the folder, it is updated in place.
+	assert.Equal(t, "new", cntr.ID)
+	assert.Equal(t, cntr, service.RemoteContainer)
+}
+
+func TestServiceStatus(t *testing.T) {
+	service := NewService(
+		&RemoteContainer{ID: "id", Name: "app", PodName: "pod"},
+		&SpecDetails{Name: "spec"})
+
+	assert.Equal(t, ServiceStopped, service.Status())
+
+	// Waiting on the first attempt.
+	service.stop = make(chan bool)
+	assert.Equal(t, ServiceStarting, service.Status())
+
+	// Waiting to try again.
+	service.lastError = "radar not ready"
+	assert.Equal(t, ServiceError, service.Status())
+
+	msg, err := service.Message()
+	assert.NoError(t, err)
+	assert.Equal(t, "error", msg.Status)
+	assert.Equal(t, "radar not ready", msg.LastError)
+}
@@ -1,13 +1,11 @@
 package ksync
 
 import (
-	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/ksync/ksync/pkg/debug"
 	"github.com/ksync/ksync/pkg/ksync/cluster"
@@ -19,8 +17,9 @@ type SpecStatus string
 
 // See docs/spec-lifecycle.png
 const (
-	SpecWaiting SpecStatus = "waiting"
-	SpecRunning SpecStatus = "running"
+	SpecWaiting      SpecStatus = "waiting"
+	SpecRunning      SpecStatus = "running"
+	SpecDisconnected SpecStatus = "disconnected"
 )
 
 // Spec is what manages the configuration and state of a folder being synced
@@ -32,7 +31,8 @@ type Spec struct {
 
 	Status SpecStatus
 
-	stopWatching chan bool
+	lock     sync.Mutex
+	watching bool
 }
 
 func (s *Spec) String() string {
@@ -44,8 +44,12 @@ func (s *Spec) Fields() log.Fields {
 	return s.Details.Fields()
 }
 
-// Message is used to serialize over gRPC
+// Message is used to serialize over gRPC. The informer updates the status
+// and services concurrently, they are read under the spec's lock.
 func (s *Spec) Message() (*pb.Spec, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
 	details, err := s.Details.Message()
 	if err != nil {
 		return nil, err
@@ -95,117 +99,192 @@ func NewSpec(details *SpecDetails) *Spec {
 // Watch will contact the cluster's api server and start watching for events
 // that match the spec. If a match is found, a new service is started up to
 // manage syncing the folder. If a event shows that the match is going away,
-// the running service is stopped.
+// the running service is stopped. Specs with the same namespace and selector
+// share a single informer.
 func (s *Spec) Watch() error {
-	if s.stopWatching != nil {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.watching {
 		log.WithFields(s.Fields()).Debug("already watching")
 		return nil
 	}
 
-	selectors := strings.Join(s.Details.Selector, ",")
+	log.WithFields(s.Fields()).Debug("watching for updates")
 
-	opts := metav1.ListOptions{}
-	opts.LabelSelector = selectors
-	watcher, err := cluster.Client.CoreV1().Pods(s.Details.Namespace).Watch(opts)
-	if err != nil {
-		return err
+	s.watching = true
+	watchPods(s)
+
+	return nil
+}
+
+// disconnect is called by the informer when it is unable to reach the
+// cluster. Services are left alone, they are reconciled once the cluster is
+// back.
+func (s *Spec) disconnect() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Status == SpecDisconnected {
+		return
 	}
 
-	log.WithFields(s.Fields()).Debug("watching for updates")
+	log.WithFields(s.Fields()).Warn("lost connection to cluster")
+	s.Status = SpecDisconnected
+}
 
-	s.stopWatching = make(chan bool)
-	go func() {
-		defer watcher.Stop()
-		for {
-			select {
-			case <-s.stopWatching:
-				log.WithFields(s.Fields()).Debug("stopping watch")
-				return
-
-			case event := <-watcher.ResultChan():
-				// For whatever reason under the sun, if the watcher looses the
-				// connection with the cluster, it ends up sending empty events
-				// as fast as possible. We want to just kill this when that's the
-				// case.
-				if event.Type == "" && event.Object == nil {
-					log.WithFields(s.Fields()).Error("lost connection to cluster")
-					SignalLoss <- true
-					return
-				}
-
-				if event.Object == nil {
-					continue
-				}
-
-				if err := s.handleEvent(event); err != nil {
-					log.WithFields(s.Fields()).Error(err)
-				}
-			}
+// reconcile brings the services in line with the pods that are running right
+// now. Pods that went away while disconnected are stopped and new pods are
+// started. Services for pods that are still around are restarted, as their
+// tunnels went down with the connection.
+func (s *Spec) reconcile(pods []v1.Pod) {
+	log.WithFields(s.Fields()).Info("reconnected to cluster")
+
+	running := map[string]*v1.Pod{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == v1.PodRunning && pod.DeletionTimestamp == nil {
+			running[pod.Name] = pod
 		}
-	}()
+	}
 
-	return nil
-}
+	// Stopping and restarting services talks to the cluster. That happens once
+	// the list is up to date and the lock has been released, so that the status
+	// can be read in the meantime.
+	work := []specWork{}
+
+	s.lock.Lock()
+	services := append([]*Service{}, s.Services.Items...)
+	for _, service := range services {
+		if _, ok := running[service.RemoteContainer.PodName]; !ok {
+			work = append(work, s.stopService(service.RemoteContainer.PodName))
+			continue
+		}
 
-func (s *Spec) handleEvent(event watch.Event) error {
-	if event.Type != watch.Modified && event.Type != watch.Added {
-		return nil
+		work = append(work, specWork{service.ShortFields(), service.Restart})
 	}
-	pod := event.Object.(*v1.Pod)
 
+	for _, pod := range running {
+		work = append(work, s.addService(pod))
+	}
+
+	s.updateStatus()
+	s.lock.Unlock()
+
+	s.run(work...)
+}
+
+// handlePod is called for every added or updated pod, as well as for each pod
+// on resync.
+func (s *Spec) handlePod(pod *v1.Pod) {
 	log.WithFields(log.Fields{
-		"type":    event.Type,
 		"name":    pod.Name,
 		"status":  pod.Status.Phase,
 		"deleted": pod.DeletionTimestamp != nil,
 	}).Debug("new event")
 
+	var work specWork
+
+	s.lock.Lock()
 	if pod.DeletionTimestamp != nil {
-		return s.cleanService(pod)
+		work = s.stopService(pod.Name)
+	} else if pod.Status.Phase == v1.PodRunning {
+		work = s.addService(pod)
 	}
 
-	if pod.Status.Phase == v1.PodRunning {
+	s.updateStatus()
+	s.lock.Unlock()
+
+	s.run(work)
+}
+
+// removePod is called when a pod has been deleted.
+func (s *Spec) removePod(pod *v1.Pod) {
+	log.WithFields(log.Fields{
+		"name": pod.Name,
+	}).Debug("pod deleted")
+
+	s.lock.Lock()
+	work := s.stopService(pod.Name)
+	s.updateStatus()
+	s.lock.Unlock()
+
+	s.run(work)
+}
+
+func (s *Spec) updateStatus() {
+	s.Status = SpecWaiting
+	if len(s.Services.Items) > 0 {
 		s.Status = SpecRunning
-		return s.addService(pod)
 	}
+}
 
-	return nil
+// specWork is something to do for a service after the spec's lock has been
+// released. Errors are logged with fields.
+type specWork struct {
+	fields log.Fields
+	fn     func() error
+}
+
+func (s *Spec) run(work ...specWork) {
+	for _, w := range work {
+		if w.fn == nil {
+			continue
+		}
+
+		if err := w.fn(); err != nil {
+			log.WithFields(w.fields).Error(err)
+		}
+	}
 }
 
-func (s *Spec) addService(pod *v1.Pod) error {
-	if err := s.Services.Add(pod, s.Details); err != nil {
+// addService updates the list of services for pod, it must be called with the
+// lock held.
+func (s *Spec) addService(pod *v1.Pod) specWork {
+	update, err := s.Services.add(pod, s.Details)
+	if err != nil {
 		if !errors.IsAlreadyExists(err) {
-			return err
+			log.WithFields(s.Fields()).Error(err)
 		}
+
+		return specWork{}
 	}
 
-	return nil
+	return specWork{s.Fields(), update}
 }
 
-func (s *Spec) cleanService(pod *v1.Pod) error {
-	service := s.Services.Pop(pod.Name)
+// stopService removes the service for podName from the list, it must be called
+// with the lock held.
+func (s *Spec) stopService(podName string) specWork {
+	service := s.Services.Pop(podName)
 	if service == nil {
 		log.WithFields(s.Fields()).Debug("service not found")
-		return nil
-	}
-
-	if err := service.Stop(); err != nil {
-		return err
+		return specWork{}
 	}
 
-	if len(s.Services.Items) == 0 {
-		s.Status = SpecWaiting
-	}
-
-	return nil
+	return specWork{service.ShortFields(), service.Stop}
 }
 
 // Cleanup will remove anything running in the background, meant to be used when
 // a spec is deleted.
 func (s *Spec) Cleanup() error {
-	if s.stopWatching != nil {
-		close(s.stopWatching)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.watching {
+		s.watching = false
+		unwatchPods(s)
+	}
+
+	if err := s.Services.Stop(); err != nil {
+		return err
 	}
 
-	return s.Services.Stop()
+	// The sidecar is only there for this spec (and maybe others), the
+	// workloads go back to how they were once nothing uses it anymore.
+	if s.Details.SyncTransport() == SpecTransportSidecar {
+		return cluster.RemoveSidecar(s.Details.Namespace, s.Details.Name)
+	}
+
+	return nil
 }
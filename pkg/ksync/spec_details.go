@@ -3,6 +3,7 @@ package ksync
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/fatih/structs"
 	"github.com/mitchellh/mapstructure"
@@ -16,6 +17,24 @@ var (
 	specEquivalenceFields = []string{"Name"}
 )
 
+// The ways files can be moved into the remote container. Syncthing goes
+// through the ksync DaemonSet and is bi-directional. Sidecar runs syncthing
+// in the pod itself, sharing the volume the remote path is on. Exec does not
+// need anything installed on the cluster, it streams tar archives into the
+// container (like `kubectl cp`) and only goes from local to remote.
+const (
+	SpecTransportSyncthing = "syncthing"
+	SpecTransportSidecar   = "sidecar"
+	SpecTransportExec      = "exec"
+)
+
+// SpecTransports are the supported ways to sync a spec.
+var SpecTransports = []string{
+	SpecTransportSyncthing,
+	SpecTransportSidecar,
+	SpecTransportExec,
+}
+
 // SpecDetails encapsulates all the configuration required to sync files between
 // a local and remote folder.
 type SpecDetails struct {
@@ -38,6 +57,9 @@ type SpecDetails struct {
 	// One-way-sync related options
 	LocalReadOnly  bool
 	RemoteReadOnly bool
+
+	// How files are moved, syncthing when empty.
+	Transport string
 }
 
 func (s *SpecDetails) String() string {
@@ -71,6 +93,7 @@ func DeserializeSpecDetails(s *pb.SpecDetails) (*SpecDetails, error) {
 		Reload:         s.GetReload(),
 		LocalReadOnly:  s.GetLocalReadOnly(),
 		RemoteReadOnly: s.GetRemoteReadOnly(),
+		Transport:      s.GetTransport(),
 	}
 
 	return result, nil
@@ -91,9 +114,33 @@ func (s *SpecDetails) IsValid() error {
 		return fmt.Errorf("local path cannot be a single file, please use a directory")
 	}
 
+	switch s.Transport {
+	case "", SpecTransportSyncthing, SpecTransportSidecar:
+	case SpecTransportExec:
+		if s.RemoteReadOnly {
+			return fmt.Errorf(
+				"the exec transport only syncs from local to remote, it cannot be used with a read-only remote")
+		}
+	default:
+		return fmt.Errorf(
+			"unsupported transport %s, must be one of: %s",
+			s.Transport,
+			strings.Join(SpecTransports, ", "))
+	}
+
 	return nil
 }
 
+// SyncTransport returns how files are moved for this spec, defaulting to
+// syncthing.
+func (s *SpecDetails) SyncTransport() string {
+	if s.Transport == "" {
+		return SpecTransportSyncthing
+	}
+
+	return s.Transport
+}
+
 // Equivalence returns a set of fields that can be used to compare specs for
 // equivalence via. reflect.DeepEqual.
 func (s *SpecDetails) Equivalence() map[string]interface{} {
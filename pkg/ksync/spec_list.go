@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/viper"
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/ksync/ksync/pkg/cli"
 	"github.com/ksync/ksync/pkg/debug"
 	pb "github.com/ksync/ksync/pkg/proto"
 )
@@ -131,6 +132,18 @@ func (s *SpecList) Watch() error {
 	return nil
 }
 
+// Reapply configures syncthing again for every running service. It is used
+// after the local syncthing process has been restarted.
+func (s *SpecList) Reapply() {
+	for _, spec := range s.Items {
+		for _, service := range spec.Services.Items {
+			if err := service.Reapply(); err != nil {
+				log.WithFields(service.ShortFields()).Error(err)
+			}
+		}
+	}
+}
+
 // Create checks an individual input spec for likeness and duplicates
 // then adds the spec into the SpecList
 func (s *SpecList) Create(details *SpecDetails, force bool) error {
@@ -185,6 +198,15 @@ func (s *SpecList) Save() error {
 	// Workaround for #91
 	delete(settings, "image")
 
+	// Written back as it is, see cluster.GetRemoteConfig.
+	remote, err := cli.ConfigSection("remote")
+	if err != nil {
+		return err
+	}
+	if remote != nil {
+		settings["remote"] = remote
+	}
+
 	buf, err := yaml.Marshal(settings)
 	if err != nil {
 		return err
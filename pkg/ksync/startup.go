@@ -0,0 +1,45 @@
+package ksync
+
+import (
+	"sync"
+)
+
+// DefaultMaxParallelStartup is how many services start at the same time unless
+// configured otherwise.
+var DefaultMaxParallelStartup = 8
+
+var (
+	// startupSlots bounds how many folders are starting at once. Starting
+	// involves tunnels, radar and both syncthing servers, large deployments
+	// would otherwise open all of those at the same time.
+	startupSlots     = make(chan struct{}, DefaultMaxParallelStartup)
+	startupSlotsLock sync.Mutex
+)
+
+// SetMaxParallelStartup changes how many services can be starting at the same
+// time. It is meant to be called before any specs are watched.
+func SetMaxParallelStartup(max int) {
+	if max < 1 {
+		max = 1
+	}
+
+	startupSlotsLock.Lock()
+	defer startupSlotsLock.Unlock()
+
+	startupSlots = make(chan struct{}, max)
+}
+
+// acquireStartup waits for a free startup slot. It returns false if stop is
+// closed first. The returned function gives the slot back.
+func acquireStartup(stop chan bool) (func(), bool) {
+	startupSlotsLock.Lock()
+	slots := startupSlots
+	startupSlotsLock.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, true
+	case <-stop:
+		return nil, false
+	}
+}
@@ -0,0 +1,28 @@
+package ksync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquireStartup(t *testing.T) {
+	defer SetMaxParallelStartup(DefaultMaxParallelStartup)
+	SetMaxParallelStartup(1)
+
+	stop := make(chan bool)
+
+	release, ok := acquireStartup(stop)
+	assert.True(t, ok)
+
+	// Every slot is taken, waiting is abandoned when stopped.
+	close(stop)
+	_, ok = acquireStartup(stop)
+	assert.False(t, ok)
+
+	release()
+
+	release, ok = acquireStartup(make(chan bool))
+	assert.True(t, ok)
+	release()
+}
@@ -10,9 +10,14 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/cenkalti/backoff"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
 
 	"github.com/ksync/ksync/pkg/cli"
 	"github.com/ksync/ksync/pkg/debug"
@@ -23,12 +28,26 @@ var (
 	duplicateListenerError = "Something is running on 8384 (run 'lsof -i :8384' to find out). Please stop that process before continuing."
 )
 
-// SignalLoss is a channel for communicating when contact with a cluster has been lost
-var SignalLoss = make(chan bool)
+// maxRestartTime is how long the supervisor tries to bring syncthing back
+// before giving up.
+var maxRestartTime = 5 * time.Minute
+
+// errStopping is returned when syncthing is started after Stop.
+var errStopping = fmt.Errorf("syncthing is stopping")
 
 // Syncthing represents the local syncthing process.
 type Syncthing struct {
 	cmd *exec.Cmd
+
+	lock         sync.Mutex
+	stopping     bool
+	running      bool
+	done         chan bool
+	failed       chan error
+	restarts     int
+	lastExit     string
+	lastRestart  time.Time
+	restartHooks []func()
 }
 
 // NewSyncthing constructs a new Syncthing.
@@ -46,19 +65,19 @@ func (s *Syncthing) Fields() log.Fields {
 }
 
 // Propogate stdout/stderr into the ksync logs for debugging.
-func (s *Syncthing) outputHandler() error {
+func (s *Syncthing) outputHandler(cmd *exec.Cmd) error {
 	logger := log.WithFields(log.Fields{
 		"name": "syncthing",
 	})
 
-	stdout, err := s.cmd.StdoutPipe()
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return err
 	}
 
 	outScanner := bufio.NewScanner(stdout)
 
-	stderr, err := s.cmd.StderrPipe()
+	stderr, err := cmd.StderrPipe()
 	if err != nil {
 		return err
 	}
@@ -112,16 +131,78 @@ func (s *Syncthing) Fetch() error {
 	return syncthing.Fetch(s.binPath())
 } // nolint: gosec
 
-// To make sure no odd devices or folders are being synced after edge cases
-// (such as the process being kill'd), the config and db are blown away before
-// each run.
-func (s *Syncthing) resetState() error {
-	base := filepath.Join(cli.ConfigPath(), "syncthing")
-	if err := os.RemoveAll(base); err != nil {
+// The configuration, index database and device identity are kept between runs
+// so that folders do not need to be rehashed every time watch starts. The
+// configuration is only created when it does not exist yet. Anything left over
+// from previous runs is removed by CleanState.
+func (s *Syncthing) prepareState() error {
+	path := filepath.Join(cli.ConfigPath(), "syncthing", "config.xml")
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
 		return err
 	}
 
-	return syncthing.ResetConfig(filepath.Join(base, "config.xml"))
+	return syncthing.ResetConfig(path)
+}
+
+// CleanState removes the folders and devices from the running syncthing that
+// do not belong to a running pod of a spec in list anymore. Folders are labeled
+// with the name of their spec and their id includes the pod (see folderID),
+// devices are kept as long as a remaining folder is shared with them. This
+// needs to run before the specs start watching.
+func (s *Syncthing) CleanState(list *SpecList) error {
+	server, err := syncthing.NewServer(
+		fmt.Sprintf("localhost:%d", viper.GetInt("syncthing-port")),
+		viper.GetString("apikey"))
+	if err != nil {
+		return err
+	}
+
+	live, err := liveFolders(list)
+	if err != nil {
+		return err
+	}
+
+	devices := map[protocol.DeviceID]bool{server.ID: true}
+
+	folders := append([]config.FolderConfiguration{}, server.Config.Folders...)
+	for _, folder := range folders {
+		spec, ok := list.Items[folder.Label]
+		if ok && live[folder.ID] && spec.Details.LocalPath == folder.Path {
+			for _, device := range folder.Devices {
+				devices[device.DeviceID] = true
+			}
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"folder": folder.ID,
+			"path":   folder.Path,
+		}).Debug("removing stale folder")
+
+		if err := server.RemoveFolder(folder.ID); err != nil {
+			return err
+		}
+	}
+
+	staleDevices := append([]config.DeviceConfiguration{}, server.Config.Devices...)
+	for _, device := range staleDevices {
+		if devices[device.DeviceID] {
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"device": device.DeviceID,
+			"name":   device.Name,
+		}).Debug("removing stale device")
+
+		if err := server.RemoveDevice(device.DeviceID); err != nil {
+			return err
+		}
+	}
+
+	return server.Update()
 }
 
 // Normally, syscall.Kill would be good enough. Unfortunately, that's not
@@ -168,21 +249,8 @@ func (s *Syncthing) cleanupDaemon(pidPath string) error {
 	return nil
 }
 
-// Run starts up a local syncthing process to serve files from.
-func (s *Syncthing) Run() error {
-	pidPath := filepath.Join(cli.ConfigPath(), "syncthing.pid")
-	if !s.HasBinary() {
-		return fmt.Errorf("missing pre-requisites, run init to fix")
-	}
-
-	if err := s.resetState(); err != nil {
-		return err
-	}
-
-	if err := s.cleanupDaemon(pidPath); err != nil {
-		return err
-	}
-
+// Start the syncthing binary in the background and record its pid.
+func (s *Syncthing) start() error {
 	path := filepath.Join(cli.ConfigPath(), "bin", "syncthing")
 
 	address := fmt.Sprintf("localhost:%d", viper.GetInt("syncthing-port"))
@@ -194,53 +262,171 @@ func (s *Syncthing) Run() error {
 		"-no-browser",
 	}
 
-	s.cmd = exec.Command(path, cmdArgs...) //nolint: gas, gosec
+	cmd := exec.Command(path, cmdArgs...) //nolint: gas, gosec
 
 	// These need to change by platform.
-	s.cmd.SysProcAttr = syncthingProcAttr
+	cmd.SysProcAttr = syncthingProcAttr
 
-	if err := s.outputHandler(); err != nil {
+	if err := s.outputHandler(cmd); err != nil {
 		return err
 	}
 
-	if err := s.cmd.Start(); err != nil {
+	if err := cmd.Start(); err != nil {
 		return err
 	}
 
+	// Stop might have signaled the previous process while this one was being
+	// started, it would be left running.
+	s.lock.Lock()
+	stopping := s.stopping
+	if !stopping {
+		s.cmd = cmd
+		s.running = true
+	}
+	s.lock.Unlock()
+
+	if stopping {
+		cmd.Process.Kill() // nolint: errcheck
+		cmd.Wait()         // nolint: errcheck
+		return backoff.Permanent(errStopping)
+	}
+
 	// Because child process signal handling is completely broken, just save the
 	// pid and try to kill it every start.
 	if err := ioutil.WriteFile(
-		pidPath,
-		[]byte(strconv.Itoa(s.cmd.Process.Pid)),
+		s.pidPath(),
+		[]byte(strconv.Itoa(cmd.Process.Pid)),
 		0600); err != nil {
 		return err
 	}
 
-	// This is horrific, but spin off a process to check for signals about LOS
-	// (Loss Of Signal) from the cluster. Cleanup and bail if we get one.
-	go func() {
-		for { // nolint: staticcheck, gosimple
-			select {
-			case <-SignalLoss:
-				log.WithFields(s.Fields()).Info("signal loss dectected. shutting down")
-				s.Stop() // nolint: errcheck, gosec
-				os.Exit(1)
+	log.WithFields(log.Fields{
+		"cmd":  cmd.Path,
+		"args": cmd.Args,
+	}).Debug("starting syncthing")
+
+	return nil
+}
+
+func (s *Syncthing) pidPath() string {
+	return filepath.Join(cli.ConfigPath(), "syncthing.pid")
+}
+
+// supervise waits for the syncthing process to exit and starts it back up
+// again, unless it was stopped on purpose. Once syncthing is back, the
+// restart hooks are run so that the configuration can be applied again. When
+// it can't be brought back, the error is sent to Failed.
+func (s *Syncthing) supervise() {
+	defer close(s.done)
+
+	for {
+		s.lock.Lock()
+		cmd := s.cmd
+		s.lock.Unlock()
+
+		err := cmd.Wait()
+
+		s.lock.Lock()
+		s.running = false
+		if s.stopping {
+			s.lock.Unlock()
+			return
+		}
+
+		s.restarts++
+		s.lastRestart = time.Now()
+		s.lastExit = "exited"
+		if err != nil {
+			s.lastExit = err.Error()
+		}
+
+		log.WithFields(log.Fields{
+			"restarts": s.restarts,
+			"exit":     s.lastExit,
+		}).Warn("syncthing exited, restarting")
+		s.lock.Unlock()
+
+		restartBackoff := backoff.NewExponentialBackOff()
+		restartBackoff.MaxElapsedTime = maxRestartTime
+
+		if err := backoff.Retry(s.start, restartBackoff); err != nil {
+			if err == errStopping {
 				return
 			}
+
+			s.failed <- fmt.Errorf("unable to restart syncthing: %v", err)
+			return
 		}
-	}()
 
-	log.WithFields(log.Fields{
-		"cmd":  s.cmd.Path,
-		"args": s.cmd.Args,
-	}).Debug("starting syncthing")
+		for _, hook := range s.restartHooks {
+			go hook()
+		}
+	}
+}
+
+// Failed receives an error when syncthing has exited and could not be
+// restarted.
+func (s *Syncthing) Failed() <-chan error {
+	return s.failed
+}
+
+// OnRestart registers a function that runs every time the supervisor has
+// restarted syncthing.
+func (s *Syncthing) OnRestart(hook func()) {
+	s.restartHooks = append(s.restartHooks, hook)
+}
+
+// Restarts returns how often syncthing has been restarted by the supervisor,
+// along with the reason for and time of the last exit.
+func (s *Syncthing) Restarts() (int, string, time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.restarts, s.lastExit, s.lastRestart
+}
+
+// Run starts up a local syncthing process to serve files from. The process is
+// supervised and restarted if it exits.
+func (s *Syncthing) Run() error {
+	if !s.HasBinary() {
+		return fmt.Errorf("missing pre-requisites, run init to fix")
+	}
+
+	if err := s.prepareState(); err != nil {
+		return err
+	}
+
+	if err := s.cleanupDaemon(s.pidPath()); err != nil {
+		return err
+	}
+
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	s.done = make(chan bool)
+	s.failed = make(chan error, 1)
+	go s.supervise()
 
 	return nil
 }
 
-// Stop halts the background process and cleans up.
+// Stop halts the background process and cleans up. While syncthing is being
+// restarted, there is nothing to signal, the supervisor stops it instead.
 func (s *Syncthing) Stop() error {
-	defer s.cmd.Process.Wait() // nolint: errcheck
+	s.lock.Lock()
+	s.stopping = true
+	cmd := s.cmd
+	running := s.running
+	s.lock.Unlock()
+
+	if s.done != nil {
+		defer func() { <-s.done }()
+	}
+
+	if !running {
+		return nil
+	}
 
-	return s.cmd.Process.Signal(os.Interrupt)
+	return cmd.Process.Signal(os.Interrupt)
 }
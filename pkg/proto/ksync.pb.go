@@ -35,7 +35,7 @@ func (m *SpecList) Reset()         { *m = SpecList{} }
 func (m *SpecList) String() string { return proto.CompactTextString(m) }
 func (*SpecList) ProtoMessage()    {}
 func (*SpecList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ksync_95ef7fbf89635b17, []int{0}
+	return fileDescriptor_ksync_ec357343ab669e16, []int{0}
 }
 func (m *SpecList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SpecList.Unmarshal(m, b)
@@ -75,7 +75,7 @@ func (m *Spec) Reset()         { *m = Spec{} }
 func (m *Spec) String() string { return proto.CompactTextString(m) }
 func (*Spec) ProtoMessage()    {}
 func (*Spec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ksync_95ef7fbf89635b17, []int{1}
+	return fileDescriptor_ksync_ec357343ab669e16, []int{1}
 }
 func (m *Spec) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Spec.Unmarshal(m, b)
@@ -127,6 +127,7 @@ type SpecDetails struct {
 	Reload               bool     `protobuf:"varint,8,opt,name=reload" json:"reload,omitempty"`
 	LocalReadOnly        bool     `protobuf:"varint,9,opt,name=local_read_only,json=localReadOnly" json:"local_read_only,omitempty"`
 	RemoteReadOnly       bool     `protobuf:"varint,10,opt,name=remote_read_only,json=remoteReadOnly" json:"remote_read_only,omitempty"`
+	Transport            string   `protobuf:"bytes,11,opt,name=transport" json:"transport,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -136,7 +137,7 @@ func (m *SpecDetails) Reset()         { *m = SpecDetails{} }
 func (m *SpecDetails) String() string { return proto.CompactTextString(m) }
 func (*SpecDetails) ProtoMessage()    {}
 func (*SpecDetails) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ksync_95ef7fbf89635b17, []int{2}
+	return fileDescriptor_ksync_ec357343ab669e16, []int{2}
 }
 func (m *SpecDetails) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SpecDetails.Unmarshal(m, b)
@@ -226,6 +227,13 @@ func (m *SpecDetails) GetRemoteReadOnly() bool {
 	return false
 }
 
+func (m *SpecDetails) GetTransport() string {
+	if m != nil {
+		return m.Transport
+	}
+	return ""
+}
+
 type ServiceList struct {
 	Items                []*Service `protobuf:"bytes,1,rep,name=items" json:"items,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -237,7 +245,7 @@ func (m *ServiceList) Reset()         { *m = ServiceList{} }
 func (m *ServiceList) String() string { return proto.CompactTextString(m) }
 func (*ServiceList) ProtoMessage()    {}
 func (*ServiceList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ksync_95ef7fbf89635b17, []int{3}
+	return fileDescriptor_ksync_ec357343ab669e16, []int{3}
 }
 func (m *ServiceList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ServiceList.Unmarshal(m, b)
@@ -265,19 +273,26 @@ func (m *ServiceList) GetItems() []*Service {
 }
 
 type Service struct {
-	SpecDetails          *SpecDetails     `protobuf:"bytes,1,opt,name=spec_details,json=specDetails" json:"spec_details,omitempty"`
-	RemoteContainer      *RemoteContainer `protobuf:"bytes,2,opt,name=remote_container,json=remoteContainer" json:"remote_container,omitempty"`
-	Status               string           `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	SpecDetails     *SpecDetails     `protobuf:"bytes,1,opt,name=spec_details,json=specDetails" json:"spec_details,omitempty"`
+	RemoteContainer *RemoteContainer `protobuf:"bytes,2,opt,name=remote_container,json=remoteContainer" json:"remote_container,omitempty"`
+	Status          string           `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	// Why the last attempt to start syncing failed, empty if it never has.
+	LastError string `protobuf:"bytes,4,opt,name=last_error,json=lastError" json:"last_error,omitempty"`
+	// When the last attempt failed, in seconds since the epoch.
+	LastErrorTime int64 `protobuf:"varint,5,opt,name=last_error_time,json=lastErrorTime" json:"last_error_time,omitempty"`
+	Attempts      int32 `protobuf:"varint,6,opt,name=attempts" json:"attempts,omitempty"`
+	// How long it took to start syncing, in milliseconds. Zero until it has.
+	StartupMs            int64    `protobuf:"varint,7,opt,name=startup_ms,json=startupMs" json:"startup_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Service) Reset()         { *m = Service{} }
 func (m *Service) String() string { return proto.CompactTextString(m) }
 func (*Service) ProtoMessage()    {}
 func (*Service) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ksync_95ef7fbf89635b17, []int{4}
+	return fileDescriptor_ksync_ec357343ab669e16, []int{4}
 }
 func (m *Service) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Service.Unmarshal(m, b)
@@ -318,11 +333,40 @@ func (m *Service) GetStatus() string {
 	return ""
 }
 
+func (m *Service) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *Service) GetLastErrorTime() int64 {
+	if m != nil {
+		return m.LastErrorTime
+	}
+	return 0
+}
+
+func (m *Service) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *Service) GetStartupMs() int64 {
+	if m != nil {
+		return m.StartupMs
+	}
+	return 0
+}
+
 type RemoteContainer struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	ContainerName        string   `protobuf:"bytes,2,opt,name=container_name,json=containerName" json:"container_name,omitempty"`
 	NodeName             string   `protobuf:"bytes,3,opt,name=node_name,json=nodeName" json:"node_name,omitempty"`
 	PodName              string   `protobuf:"bytes,4,opt,name=pod_name,json=podName" json:"pod_name,omitempty"`
+	Runtime              string   `protobuf:"bytes,5,opt,name=runtime" json:"runtime,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -332,7 +376,7 @@ func (m *RemoteContainer) Reset()         { *m = RemoteContainer{} }
 func (m *RemoteContainer) String() string { return proto.CompactTextString(m) }
 func (*RemoteContainer) ProtoMessage()    {}
 func (*RemoteContainer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ksync_95ef7fbf89635b17, []int{5}
+	return fileDescriptor_ksync_ec357343ab669e16, []int{5}
 }
 func (m *RemoteContainer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoteContainer.Unmarshal(m, b)
@@ -380,8 +424,20 @@ func (m *RemoteContainer) GetPodName() string {
 	return ""
 }
 
+func (m *RemoteContainer) GetRuntime() string {
+	if m != nil {
+		return m.Runtime
+	}
+	return ""
+}
+
 type Alive struct {
-	Alive                bool     `protobuf:"varint,1,opt,name=alive" json:"alive,omitempty"`
+	Alive bool `protobuf:"varint,1,opt,name=alive" json:"alive,omitempty"`
+	// How often the local syncthing has been restarted after exiting.
+	Restarts int32  `protobuf:"varint,2,opt,name=restarts" json:"restarts,omitempty"`
+	LastExit string `protobuf:"bytes,3,opt,name=last_exit,json=lastExit" json:"last_exit,omitempty"`
+	// When syncthing was last restarted, in unix seconds.
+	LastRestart          int64    `protobuf:"varint,4,opt,name=last_restart,json=lastRestart" json:"last_restart,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -391,7 +447,7 @@ func (m *Alive) Reset()         { *m = Alive{} }
 func (m *Alive) String() string { return proto.CompactTextString(m) }
 func (*Alive) ProtoMessage()    {}
 func (*Alive) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ksync_95ef7fbf89635b17, []int{6}
+	return fileDescriptor_ksync_ec357343ab669e16, []int{6}
 }
 func (m *Alive) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Alive.Unmarshal(m, b)
@@ -418,6 +474,182 @@ func (m *Alive) GetAlive() bool {
 	return false
 }
 
+func (m *Alive) GetRestarts() int32 {
+	if m != nil {
+		return m.Restarts
+	}
+	return 0
+}
+
+func (m *Alive) GetLastExit() string {
+	if m != nil {
+		return m.LastExit
+	}
+	return ""
+}
+
+func (m *Alive) GetLastRestart() int64 {
+	if m != nil {
+		return m.LastRestart
+	}
+	return 0
+}
+
+type ConnectionList struct {
+	Items                []*NodeConnection `protobuf:"bytes,1,rep,name=items" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ConnectionList) Reset()         { *m = ConnectionList{} }
+func (m *ConnectionList) String() string { return proto.CompactTextString(m) }
+func (*ConnectionList) ProtoMessage()    {}
+func (*ConnectionList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ksync_ec357343ab669e16, []int{7}
+}
+func (m *ConnectionList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionList.Unmarshal(m, b)
+}
+func (m *ConnectionList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionList.Marshal(b, m, deterministic)
+}
+func (dst *ConnectionList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionList.Merge(dst, src)
+}
+func (m *ConnectionList) XXX_Size() int {
+	return xxx_messageInfo_ConnectionList.Size(m)
+}
+func (m *ConnectionList) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionList proto.InternalMessageInfo
+
+func (m *ConnectionList) GetItems() []*NodeConnection {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+type NodeConnection struct {
+	NodeName string `protobuf:"bytes,1,opt,name=node_name,json=nodeName" json:"node_name,omitempty"`
+	// How many folders are using the connection.
+	Refs                 int32     `protobuf:"varint,2,opt,name=refs" json:"refs,omitempty"`
+	Tunnels              []*Tunnel `protobuf:"bytes,3,rep,name=tunnels" json:"tunnels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *NodeConnection) Reset()         { *m = NodeConnection{} }
+func (m *NodeConnection) String() string { return proto.CompactTextString(m) }
+func (*NodeConnection) ProtoMessage()    {}
+func (*NodeConnection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ksync_ec357343ab669e16, []int{8}
+}
+func (m *NodeConnection) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeConnection.Unmarshal(m, b)
+}
+func (m *NodeConnection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeConnection.Marshal(b, m, deterministic)
+}
+func (dst *NodeConnection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeConnection.Merge(dst, src)
+}
+func (m *NodeConnection) XXX_Size() int {
+	return xxx_messageInfo_NodeConnection.Size(m)
+}
+func (m *NodeConnection) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeConnection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeConnection proto.InternalMessageInfo
+
+func (m *NodeConnection) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *NodeConnection) GetRefs() int32 {
+	if m != nil {
+		return m.Refs
+	}
+	return 0
+}
+
+func (m *NodeConnection) GetTunnels() []*Tunnel {
+	if m != nil {
+		return m.Tunnels
+	}
+	return nil
+}
+
+type Tunnel struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	LocalPort            int32    `protobuf:"varint,2,opt,name=local_port,json=localPort" json:"local_port,omitempty"`
+	RemotePort           int32    `protobuf:"varint,3,opt,name=remote_port,json=remotePort" json:"remote_port,omitempty"`
+	Healthy              bool     `protobuf:"varint,4,opt,name=healthy" json:"healthy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Tunnel) Reset()         { *m = Tunnel{} }
+func (m *Tunnel) String() string { return proto.CompactTextString(m) }
+func (*Tunnel) ProtoMessage()    {}
+func (*Tunnel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ksync_ec357343ab669e16, []int{9}
+}
+func (m *Tunnel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Tunnel.Unmarshal(m, b)
+}
+func (m *Tunnel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Tunnel.Marshal(b, m, deterministic)
+}
+func (dst *Tunnel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Tunnel.Merge(dst, src)
+}
+func (m *Tunnel) XXX_Size() int {
+	return xxx_messageInfo_Tunnel.Size(m)
+}
+func (m *Tunnel) XXX_DiscardUnknown() {
+	xxx_messageInfo_Tunnel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Tunnel proto.InternalMessageInfo
+
+func (m *Tunnel) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Tunnel) GetLocalPort() int32 {
+	if m != nil {
+		return m.LocalPort
+	}
+	return 0
+}
+
+func (m *Tunnel) GetRemotePort() int32 {
+	if m != nil {
+		return m.RemotePort
+	}
+	return 0
+}
+
+func (m *Tunnel) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*SpecList)(nil), "proto.ksync.SpecList")
 	proto.RegisterMapType((map[string]*Spec)(nil), "proto.ksync.SpecList.ItemsEntry")
@@ -427,6 +659,9 @@ func init() {
 	proto.RegisterType((*Service)(nil), "proto.ksync.Service")
 	proto.RegisterType((*RemoteContainer)(nil), "proto.ksync.RemoteContainer")
 	proto.RegisterType((*Alive)(nil), "proto.ksync.Alive")
+	proto.RegisterType((*ConnectionList)(nil), "proto.ksync.ConnectionList")
+	proto.RegisterType((*NodeConnection)(nil), "proto.ksync.NodeConnection")
+	proto.RegisterType((*Tunnel)(nil), "proto.ksync.Tunnel")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -444,6 +679,7 @@ type KsyncClient interface {
 	GetSpecList(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*SpecList, error)
 	RestartSyncthing(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Error, error)
 	IsAlive(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Alive, error)
+	GetConnections(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ConnectionList, error)
 }
 
 type ksyncClient struct {
@@ -481,12 +717,22 @@ func (c *ksyncClient) IsAlive(ctx context.Context, in *empty.Empty, opts ...grpc
 	return out, nil
 }
 
+func (c *ksyncClient) GetConnections(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ConnectionList, error) {
+	out := new(ConnectionList)
+	err := c.cc.Invoke(ctx, "/proto.ksync.Ksync/GetConnections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Ksync service
 
 type KsyncServer interface {
 	GetSpecList(context.Context, *empty.Empty) (*SpecList, error)
 	RestartSyncthing(context.Context, *empty.Empty) (*Error, error)
 	IsAlive(context.Context, *empty.Empty) (*Alive, error)
+	GetConnections(context.Context, *empty.Empty) (*ConnectionList, error)
 }
 
 func RegisterKsyncServer(s *grpc.Server, srv KsyncServer) {
@@ -547,6 +793,24 @@ func _Ksync_IsAlive_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ksync_GetConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KsyncServer).GetConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ksync.Ksync/GetConnections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KsyncServer).GetConnections(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Ksync_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.ksync.Ksync",
 	HandlerType: (*KsyncServer)(nil),
@@ -563,51 +827,70 @@ var _Ksync_serviceDesc = grpc.ServiceDesc{
 			MethodName: "IsAlive",
 			Handler:    _Ksync_IsAlive_Handler,
 		},
+		{
+			MethodName: "GetConnections",
+			Handler:    _Ksync_GetConnections_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/ksync.proto",
 }
 
-func init() { proto.RegisterFile("proto/ksync.proto", fileDescriptor_ksync_95ef7fbf89635b17) }
-
-var fileDescriptor_ksync_95ef7fbf89635b17 = []byte{
-	// 602 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcd, 0x6a, 0xdb, 0x4c,
-	0x14, 0x8d, 0x64, 0xcb, 0x96, 0xaf, 0xbe, 0x24, 0xce, 0x90, 0xcf, 0xa8, 0x4e, 0x42, 0x85, 0xa0,
-	0xad, 0xe8, 0x42, 0x06, 0xb7, 0xf4, 0x17, 0x4a, 0x4b, 0x6b, 0x42, 0x48, 0x69, 0xcb, 0xe4, 0x01,
-	0xcc, 0x44, 0x9a, 0xda, 0x22, 0xb2, 0x46, 0xcc, 0x8c, 0x0d, 0xda, 0x75, 0xd9, 0x5d, 0x1f, 0xa3,
-	0xef, 0xd1, 0x4d, 0x5f, 0xab, 0x68, 0x46, 0x96, 0xed, 0x38, 0x01, 0xaf, 0x3c, 0xf7, 0x9c, 0x73,
-	0x7f, 0xc6, 0x73, 0x74, 0xe1, 0x28, 0xe7, 0x4c, 0xb2, 0xc1, 0x8d, 0x28, 0xb2, 0x28, 0x54, 0x67,
-	0xe4, 0xa8, 0x9f, 0x50, 0x41, 0xfd, 0x93, 0x09, 0x63, 0x93, 0x94, 0x0e, 0x14, 0x76, 0x3d, 0xff,
-	0x3e, 0xa0, 0xb3, 0x5c, 0x16, 0x5a, 0xd9, 0xaf, 0x92, 0x39, 0x89, 0x09, 0xd7, 0x90, 0xff, 0xcb,
-	0x00, 0xfb, 0x2a, 0xa7, 0xd1, 0xe7, 0x44, 0x48, 0xf4, 0x02, 0xac, 0x44, 0xd2, 0x99, 0x70, 0x0d,
-	0xaf, 0x11, 0x38, 0x43, 0x2f, 0x5c, 0xab, 0x1c, 0x2e, 0x55, 0xe1, 0x45, 0x29, 0x19, 0x65, 0x92,
-	0x17, 0x58, 0xcb, 0xfb, 0x97, 0x00, 0x2b, 0x10, 0x75, 0xa1, 0x71, 0x43, 0x0b, 0xd7, 0xf0, 0x8c,
-	0xa0, 0x83, 0xcb, 0x23, 0x7a, 0x02, 0xd6, 0x82, 0xa4, 0x73, 0xea, 0x9a, 0x9e, 0x11, 0x38, 0xc3,
-	0xa3, 0xad, 0xba, 0x58, 0xf3, 0x6f, 0xcc, 0x57, 0x86, 0xff, 0xd3, 0x80, 0x66, 0x89, 0xa1, 0x21,
-	0xb4, 0x63, 0x2a, 0x49, 0x92, 0x0a, 0x55, 0xcb, 0x19, 0xba, 0x5b, 0x79, 0x9f, 0x34, 0x8f, 0x97,
-	0x42, 0xf4, 0x1c, 0x6c, 0x41, 0xf9, 0x22, 0x89, 0xa8, 0xa8, 0x9a, 0xdd, 0x4a, 0xd2, 0x64, 0x79,
-	0x0f, 0x5c, 0x2b, 0x51, 0x0f, 0x5a, 0x42, 0x12, 0x39, 0x17, 0x6e, 0x43, 0x0d, 0x5d, 0x45, 0xfe,
-	0x5f, 0x13, 0x9c, 0xb5, 0x36, 0x08, 0x41, 0x33, 0x23, 0x33, 0x5a, 0x5d, 0x4d, 0x9d, 0xd1, 0x23,
-	0x38, 0x88, 0x58, 0x26, 0x49, 0x92, 0x51, 0x3e, 0x56, 0xac, 0xa9, 0xd8, 0xfd, 0x1a, 0xfd, 0x52,
-	0xca, 0x1e, 0x80, 0x9d, 0xb3, 0x58, 0x0b, 0x74, 0x93, 0x76, 0xce, 0x62, 0x45, 0xf5, 0xcb, 0x99,
-	0x53, 0x1a, 0x49, 0xc6, 0xdd, 0xa6, 0xd7, 0x08, 0x3a, 0xb8, 0x8e, 0xd1, 0x29, 0x74, 0xca, 0x14,
-	0x91, 0x93, 0x88, 0xba, 0x96, 0xca, 0x5b, 0x01, 0xe8, 0x0c, 0x20, 0x65, 0x11, 0x49, 0xc7, 0x39,
-	0x91, 0x53, 0xb7, 0xa5, 0x69, 0x85, 0x7c, 0x23, 0x72, 0x8a, 0x1e, 0x82, 0xc3, 0xe9, 0x8c, 0x49,
-	0xaa, 0xf9, 0xb6, 0xe2, 0x41, 0x43, 0x4a, 0xd0, 0x83, 0x16, 0xa7, 0x29, 0x23, 0xb1, 0x6b, 0x7b,
-	0x46, 0x60, 0xe3, 0x2a, 0x42, 0x8f, 0xe1, 0x50, 0xd7, 0xe5, 0x94, 0xc4, 0x63, 0x96, 0xa5, 0x85,
-	0xdb, 0x51, 0x82, 0x7d, 0x05, 0x63, 0x4a, 0xe2, 0xaf, 0x59, 0x5a, 0xa0, 0x00, 0xba, 0x55, 0x83,
-	0x95, 0x10, 0x94, 0xf0, 0x40, 0xe3, 0x4b, 0xa5, 0xff, 0x1a, 0x9c, 0xb5, 0xbf, 0x1e, 0x3d, 0xdd,
-	0x34, 0xda, 0xf1, 0x5d, 0x6f, 0x54, 0x99, 0xcb, 0xff, 0x6d, 0x40, 0xbb, 0x82, 0xd0, 0x5b, 0xf8,
-	0x4f, 0xe4, 0x34, 0x1a, 0xef, 0xea, 0x0b, 0x47, 0xac, 0xbd, 0xde, 0x79, 0x3d, 0x6d, 0xfd, 0x34,
-	0x95, 0x47, 0x4e, 0x37, 0x0a, 0x60, 0x25, 0xfa, 0xb8, 0xd4, 0xe0, 0x43, 0xbe, 0x09, 0xdc, 0x6b,
-	0x97, 0x1f, 0x06, 0x1c, 0xde, 0x4a, 0x46, 0x07, 0x60, 0x26, 0x71, 0x65, 0x18, 0x33, 0x89, 0x77,
-	0xb5, 0xcb, 0x09, 0x74, 0x32, 0x16, 0xd3, 0x75, 0xbf, 0xd8, 0x25, 0xb0, 0xe5, 0xa5, 0xe6, 0x86,
-	0x97, 0xfc, 0x33, 0xb0, 0x3e, 0xa4, 0xc9, 0x82, 0xa2, 0x63, 0xb0, 0x48, 0x79, 0x50, 0xad, 0x6d,
-	0xac, 0x83, 0xe1, 0x1f, 0x03, 0xac, 0xcb, 0xf2, 0x92, 0xe8, 0x1d, 0x38, 0xe7, 0x54, 0xd6, 0x5f,
-	0x7e, 0x2f, 0xd4, 0x7b, 0x23, 0x5c, 0xee, 0x8d, 0x70, 0x54, 0xee, 0x8d, 0xfe, 0xff, 0x77, 0xae,
-	0x00, 0x7f, 0x0f, 0xbd, 0x87, 0x2e, 0xa6, 0x42, 0x12, 0x2e, 0xaf, 0x8a, 0x2c, 0x92, 0xd3, 0x24,
-	0x9b, 0xdc, 0x5b, 0x04, 0x6d, 0x14, 0x19, 0x71, 0xce, 0xb8, 0xbf, 0x87, 0x5e, 0x42, 0xfb, 0x42,
-	0xe8, 0x61, 0x77, 0x4b, 0x54, 0x5a, 0x7f, 0xef, 0xba, 0xa5, 0xc0, 0x67, 0xff, 0x02, 0x00, 0x00,
-	0xff, 0xff, 0x2b, 0x89, 0x30, 0xc2, 0x0b, 0x05, 0x00, 0x00,
+func init() { proto.RegisterFile("proto/ksync.proto", fileDescriptor_ksync_ec357343ab669e16) }
+
+var fileDescriptor_ksync_ec357343ab669e16 = []byte{
+	// 841 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0xdd, 0x6e, 0xe4, 0x34,
+	0x14, 0x6e, 0x32, 0x93, 0xf9, 0x39, 0xd9, 0x4e, 0xbb, 0x66, 0xa9, 0xc2, 0xb4, 0x88, 0x21, 0x12,
+	0x30, 0x42, 0x22, 0x15, 0x03, 0xe2, 0x57, 0x42, 0xa0, 0x52, 0x55, 0xab, 0x85, 0x05, 0x79, 0xf7,
+	0x7e, 0xe4, 0x4d, 0xbc, 0x9d, 0x68, 0x33, 0x76, 0x64, 0x7b, 0xaa, 0xcd, 0x1b, 0x20, 0x71, 0xc1,
+	0x3d, 0x6f, 0xc0, 0x83, 0xf1, 0x1e, 0x2b, 0x1f, 0x27, 0x99, 0x49, 0x3b, 0x95, 0x7a, 0x15, 0x9f,
+	0xef, 0xfb, 0x6c, 0x1f, 0x9f, 0xf3, 0xc5, 0x86, 0xc7, 0xa5, 0x92, 0x46, 0x9e, 0xbf, 0xd1, 0x95,
+	0x48, 0x13, 0x1c, 0x93, 0x10, 0x3f, 0x09, 0x42, 0xd3, 0xd3, 0x6b, 0x29, 0xaf, 0x0b, 0x7e, 0x8e,
+	0xd8, 0xab, 0xcd, 0xeb, 0x73, 0xbe, 0x2e, 0x4d, 0xe5, 0x94, 0xd3, 0x7a, 0xb2, 0x62, 0x19, 0x53,
+	0x0e, 0x8a, 0xff, 0xf1, 0x60, 0xf4, 0xa2, 0xe4, 0xe9, 0x6f, 0xb9, 0x36, 0xe4, 0x1b, 0x08, 0x72,
+	0xc3, 0xd7, 0x3a, 0xf2, 0x66, 0xbd, 0x79, 0xb8, 0x98, 0x25, 0x3b, 0x2b, 0x27, 0x8d, 0x2a, 0x79,
+	0x6a, 0x25, 0x97, 0xc2, 0xa8, 0x8a, 0x3a, 0xf9, 0xf4, 0x19, 0xc0, 0x16, 0x24, 0xc7, 0xd0, 0x7b,
+	0xc3, 0xab, 0xc8, 0x9b, 0x79, 0xf3, 0x31, 0xb5, 0x43, 0xf2, 0x19, 0x04, 0x37, 0xac, 0xd8, 0xf0,
+	0xc8, 0x9f, 0x79, 0xf3, 0x70, 0xf1, 0xf8, 0xce, 0xba, 0xd4, 0xf1, 0x3f, 0xf8, 0xdf, 0x79, 0xf1,
+	0x5f, 0x1e, 0xf4, 0x2d, 0x46, 0x16, 0x30, 0xcc, 0xb8, 0x61, 0x79, 0xa1, 0x71, 0xad, 0x70, 0x11,
+	0xdd, 0x99, 0xf7, 0xab, 0xe3, 0x69, 0x23, 0x24, 0x5f, 0xc3, 0x48, 0x73, 0x75, 0x93, 0xa7, 0x5c,
+	0x47, 0xfe, 0xbe, 0x49, 0x8e, 0xb4, 0xe7, 0xa0, 0xad, 0x92, 0x9c, 0xc0, 0x40, 0x1b, 0x66, 0x36,
+	0x3a, 0xea, 0x61, 0xd2, 0x75, 0x14, 0xff, 0xef, 0x43, 0xb8, 0xb3, 0x0d, 0x21, 0xd0, 0x17, 0x6c,
+	0xcd, 0xeb, 0xa3, 0xe1, 0x98, 0x7c, 0x02, 0x93, 0x54, 0x0a, 0xc3, 0x72, 0xc1, 0xd5, 0x12, 0x59,
+	0x1f, 0xd9, 0xc3, 0x16, 0x7d, 0x6e, 0x65, 0x1f, 0xc0, 0xa8, 0x94, 0x99, 0x13, 0xb8, 0x4d, 0x86,
+	0xa5, 0xcc, 0x90, 0x9a, 0xda, 0x9c, 0x0b, 0x9e, 0x1a, 0xa9, 0xa2, 0xfe, 0xac, 0x37, 0x1f, 0xd3,
+	0x36, 0x26, 0x67, 0x30, 0xb6, 0x53, 0x74, 0xc9, 0x52, 0x1e, 0x05, 0x38, 0x6f, 0x0b, 0x90, 0x0f,
+	0x01, 0x0a, 0x99, 0xb2, 0x62, 0x59, 0x32, 0xb3, 0x8a, 0x06, 0x8e, 0x46, 0xe4, 0x4f, 0x66, 0x56,
+	0xe4, 0x23, 0x08, 0x15, 0x5f, 0x4b, 0xc3, 0x1d, 0x3f, 0x44, 0x1e, 0x1c, 0x84, 0x82, 0x13, 0x18,
+	0x28, 0x5e, 0x48, 0x96, 0x45, 0xa3, 0x99, 0x37, 0x1f, 0xd1, 0x3a, 0x22, 0x9f, 0xc2, 0x91, 0x5b,
+	0x57, 0x71, 0x96, 0x2d, 0xa5, 0x28, 0xaa, 0x68, 0x8c, 0x82, 0x43, 0x84, 0x29, 0x67, 0xd9, 0x1f,
+	0xa2, 0xa8, 0xc8, 0x1c, 0x8e, 0xeb, 0x0d, 0xb6, 0x42, 0x40, 0xe1, 0xc4, 0xe1, 0xad, 0xf2, 0x0c,
+	0xc6, 0x46, 0x31, 0xa1, 0x4b, 0xa9, 0x4c, 0x14, 0xba, 0x44, 0x5b, 0x20, 0xfe, 0x1e, 0xc2, 0x9d,
+	0xc6, 0x90, 0xcf, 0xbb, 0x36, 0x7c, 0xb2, 0xaf, 0x83, 0xb5, 0xf5, 0xe2, 0xff, 0x7c, 0x18, 0xd6,
+	0x10, 0xf9, 0x11, 0x1e, 0xe9, 0x92, 0xa7, 0xcb, 0x87, 0xba, 0x26, 0xd4, 0xdb, 0x80, 0x5c, 0xb5,
+	0x67, 0x69, 0x1b, 0x57, 0x3b, 0xe8, 0xac, 0xb3, 0x00, 0x45, 0xd1, 0x45, 0xa3, 0xa1, 0x47, 0xaa,
+	0x0b, 0xdc, 0x67, 0x26, 0x6c, 0x16, 0xd3, 0x66, 0xc9, 0x95, 0xc2, 0x46, 0xbb, 0x66, 0x31, 0x6d,
+	0x2e, 0x2d, 0x80, 0x35, 0x6f, 0xe9, 0xa5, 0xc9, 0xd7, 0xae, 0xdf, 0x3d, 0x7a, 0xd8, 0x6a, 0x5e,
+	0xe6, 0xce, 0x2d, 0xcc, 0x18, 0xfb, 0x57, 0x6b, 0xec, 0x78, 0x40, 0xdb, 0xd8, 0x6e, 0xa1, 0x0d,
+	0x53, 0x66, 0x53, 0x2e, 0xd7, 0x1a, 0xfb, 0xdd, 0xa3, 0xe3, 0x1a, 0xf9, 0x5d, 0xc7, 0xff, 0x7a,
+	0x70, 0x74, 0x2b, 0x7d, 0x32, 0x01, 0x3f, 0xcf, 0x6a, 0x43, 0xfb, 0x79, 0xf6, 0x50, 0x3b, 0x9f,
+	0xc2, 0x58, 0xc8, 0x8c, 0xef, 0xfa, 0x79, 0x64, 0x81, 0x3b, 0x5e, 0xef, 0x77, 0xbd, 0x1e, 0xc1,
+	0x50, 0x6d, 0x44, 0x7b, 0xba, 0x31, 0x6d, 0xc2, 0xb8, 0x82, 0xe0, 0x97, 0x22, 0xbf, 0xe1, 0xe4,
+	0x09, 0x04, 0xcc, 0x0e, 0x30, 0xa9, 0x11, 0x75, 0x81, 0x3d, 0xb6, 0xe2, 0x78, 0x14, 0xf7, 0x63,
+	0x07, 0xb4, 0x8d, 0x6d, 0x32, 0xae, 0x74, 0x6f, 0x73, 0xd3, 0x24, 0x83, 0x45, 0x7b, 0x9b, 0x1b,
+	0xf2, 0x31, 0x3c, 0x42, 0xb2, 0x56, 0x63, 0x42, 0x3d, 0x1a, 0x5a, 0x8c, 0x3a, 0x28, 0xbe, 0x80,
+	0xc9, 0x85, 0x14, 0x82, 0xa7, 0x26, 0x97, 0x02, 0x1d, 0xf8, 0x65, 0xd7, 0x81, 0xa7, 0x1d, 0x07,
+	0x3c, 0x97, 0x19, 0xdf, 0xea, 0x1b, 0x23, 0x96, 0x30, 0xe9, 0x12, 0xdd, 0x1a, 0x79, 0xb7, 0x6a,
+	0x44, 0xa0, 0xaf, 0xf8, 0xeb, 0xe6, 0x2c, 0x38, 0x26, 0x5f, 0xc0, 0xd0, 0x6c, 0x84, 0xe0, 0x85,
+	0xb5, 0x8e, 0xdd, 0xf7, 0xbd, 0xce, 0xbe, 0x2f, 0x91, 0xa3, 0x8d, 0x26, 0xbe, 0x81, 0x81, 0x83,
+	0xf6, 0xde, 0x4b, 0xdb, 0xbb, 0xc1, 0xfe, 0x72, 0x6e, 0x9b, 0xfa, 0x6e, 0x90, 0xca, 0xec, 0xde,
+	0x0d, 0x52, 0xb9, 0xaa, 0x05, 0xed, 0xdd, 0x60, 0x05, 0x11, 0x0c, 0x57, 0x9c, 0x15, 0x66, 0x55,
+	0x61, 0xc9, 0x46, 0xb4, 0x09, 0x17, 0x7f, 0xfb, 0x10, 0x3c, 0xb3, 0x19, 0x91, 0x9f, 0x20, 0xbc,
+	0xe2, 0xa6, 0x7d, 0x3e, 0x4e, 0x12, 0xf7, 0xf8, 0x24, 0xcd, 0xe3, 0x93, 0x5c, 0xda, 0xc7, 0x67,
+	0xfa, 0xfe, 0xde, 0x77, 0x24, 0x3e, 0x20, 0x3f, 0xc3, 0x71, 0xdd, 0x83, 0x17, 0x95, 0x48, 0xcd,
+	0x2a, 0x17, 0xd7, 0xf7, 0x2e, 0x42, 0x3a, 0x8b, 0xe0, 0xff, 0x10, 0x1f, 0x90, 0x6f, 0x61, 0xf8,
+	0x54, 0x3b, 0xdf, 0x3c, 0x6c, 0x22, 0x6a, 0xe3, 0x03, 0x72, 0x05, 0x93, 0x2b, 0x6e, 0xb6, 0xdd,
+	0xd2, 0xf7, 0xce, 0xef, 0x36, 0xbf, 0x6b, 0x94, 0xf8, 0xe0, 0xd5, 0x00, 0xd9, 0xaf, 0xde, 0x0d,
+	0x00, 0x35, 0x0c, 0x14, 0x16, 0x99, 0x07, 0x00, 0x00,
 }
@@ -26,6 +26,7 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type ContainerPath struct {
 	ContainerId          string   `protobuf:"bytes,1,opt,name=container_id,json=containerId" json:"container_id,omitempty"`
+	Runtime              string   `protobuf:"bytes,2,opt,name=runtime" json:"runtime,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -35,7 +36,7 @@ func (m *ContainerPath) Reset()         { *m = ContainerPath{} }
 func (m *ContainerPath) String() string { return proto.CompactTextString(m) }
 func (*ContainerPath) ProtoMessage()    {}
 func (*ContainerPath) Descriptor() ([]byte, []int) {
-	return fileDescriptor_radar_33960b4f5aee43a0, []int{0}
+	return fileDescriptor_radar_c41d305e714fd013, []int{0}
 }
 func (m *ContainerPath) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ContainerPath.Unmarshal(m, b)
@@ -62,6 +63,13 @@ func (m *ContainerPath) GetContainerId() string {
 	return ""
 }
 
+func (m *ContainerPath) GetRuntime() string {
+	if m != nil {
+		return m.Runtime
+	}
+	return ""
+}
+
 type BasePath struct {
 	Full                 string   `protobuf:"bytes,1,opt,name=full" json:"full,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -73,7 +81,7 @@ func (m *BasePath) Reset()         { *m = BasePath{} }
 func (m *BasePath) String() string { return proto.CompactTextString(m) }
 func (*BasePath) ProtoMessage()    {}
 func (*BasePath) Descriptor() ([]byte, []int) {
-	return fileDescriptor_radar_33960b4f5aee43a0, []int{1}
+	return fileDescriptor_radar_c41d305e714fd013, []int{1}
 }
 func (m *BasePath) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BasePath.Unmarshal(m, b)
@@ -111,7 +119,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_radar_33960b4f5aee43a0, []int{2}
+	return fileDescriptor_radar_c41d305e714fd013, []int{2}
 }
 func (m *Error) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Error.Unmarshal(m, b)
@@ -153,7 +161,7 @@ func (m *VersionInfo) Reset()         { *m = VersionInfo{} }
 func (m *VersionInfo) String() string { return proto.CompactTextString(m) }
 func (*VersionInfo) ProtoMessage()    {}
 func (*VersionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_radar_33960b4f5aee43a0, []int{3}
+	return fileDescriptor_radar_c41d305e714fd013, []int{3}
 }
 func (m *VersionInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VersionInfo.Unmarshal(m, b)
@@ -225,7 +233,7 @@ func (m *DockerVersion) Reset()         { *m = DockerVersion{} }
 func (m *DockerVersion) String() string { return proto.CompactTextString(m) }
 func (*DockerVersion) ProtoMessage()    {}
 func (*DockerVersion) Descriptor() ([]byte, []int) {
-	return fileDescriptor_radar_33960b4f5aee43a0, []int{4}
+	return fileDescriptor_radar_c41d305e714fd013, []int{4}
 }
 func (m *DockerVersion) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DockerVersion.Unmarshal(m, b)
@@ -307,7 +315,7 @@ func (m *DockerInfo) Reset()         { *m = DockerInfo{} }
 func (m *DockerInfo) String() string { return proto.CompactTextString(m) }
 func (*DockerInfo) ProtoMessage()    {}
 func (*DockerInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_radar_33960b4f5aee43a0, []int{5}
+	return fileDescriptor_radar_c41d305e714fd013, []int{5}
 }
 func (m *DockerInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DockerInfo.Unmarshal(m, b)
@@ -348,6 +356,160 @@ func (m *DockerInfo) GetDockerRoot() string {
 	return ""
 }
 
+type Runtime struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Runtime) Reset()         { *m = Runtime{} }
+func (m *Runtime) String() string { return proto.CompactTextString(m) }
+func (*Runtime) ProtoMessage()    {}
+func (*Runtime) Descriptor() ([]byte, []int) {
+	return fileDescriptor_radar_c41d305e714fd013, []int{6}
+}
+func (m *Runtime) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Runtime.Unmarshal(m, b)
+}
+func (m *Runtime) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Runtime.Marshal(b, m, deterministic)
+}
+func (dst *Runtime) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Runtime.Merge(dst, src)
+}
+func (m *Runtime) XXX_Size() int {
+	return xxx_messageInfo_Runtime.Size(m)
+}
+func (m *Runtime) XXX_DiscardUnknown() {
+	xxx_messageInfo_Runtime.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Runtime proto.InternalMessageInfo
+
+func (m *Runtime) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type RuntimeVersion struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=Version" json:"Version,omitempty"`
+	APIVersion           string   `protobuf:"bytes,3,opt,name=APIVersion" json:"APIVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RuntimeVersion) Reset()         { *m = RuntimeVersion{} }
+func (m *RuntimeVersion) String() string { return proto.CompactTextString(m) }
+func (*RuntimeVersion) ProtoMessage()    {}
+func (*RuntimeVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_radar_c41d305e714fd013, []int{7}
+}
+func (m *RuntimeVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RuntimeVersion.Unmarshal(m, b)
+}
+func (m *RuntimeVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RuntimeVersion.Marshal(b, m, deterministic)
+}
+func (dst *RuntimeVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RuntimeVersion.Merge(dst, src)
+}
+func (m *RuntimeVersion) XXX_Size() int {
+	return xxx_messageInfo_RuntimeVersion.Size(m)
+}
+func (m *RuntimeVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_RuntimeVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RuntimeVersion proto.InternalMessageInfo
+
+func (m *RuntimeVersion) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RuntimeVersion) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *RuntimeVersion) GetAPIVersion() string {
+	if m != nil {
+		return m.APIVersion
+	}
+	return ""
+}
+
+type RuntimeInfo struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
+	Driver               string   `protobuf:"bytes,2,opt,name=Driver" json:"Driver,omitempty"`
+	DriverStatus         []string `protobuf:"bytes,3,rep,name=DriverStatus" json:"DriverStatus,omitempty"`
+	Root                 string   `protobuf:"bytes,4,opt,name=Root" json:"Root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RuntimeInfo) Reset()         { *m = RuntimeInfo{} }
+func (m *RuntimeInfo) String() string { return proto.CompactTextString(m) }
+func (*RuntimeInfo) ProtoMessage()    {}
+func (*RuntimeInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_radar_c41d305e714fd013, []int{8}
+}
+func (m *RuntimeInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RuntimeInfo.Unmarshal(m, b)
+}
+func (m *RuntimeInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RuntimeInfo.Marshal(b, m, deterministic)
+}
+func (dst *RuntimeInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RuntimeInfo.Merge(dst, src)
+}
+func (m *RuntimeInfo) XXX_Size() int {
+	return xxx_messageInfo_RuntimeInfo.Size(m)
+}
+func (m *RuntimeInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_RuntimeInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RuntimeInfo proto.InternalMessageInfo
+
+func (m *RuntimeInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RuntimeInfo) GetDriver() string {
+	if m != nil {
+		return m.Driver
+	}
+	return ""
+}
+
+func (m *RuntimeInfo) GetDriverStatus() []string {
+	if m != nil {
+		return m.DriverStatus
+	}
+	return nil
+}
+
+func (m *RuntimeInfo) GetRoot() string {
+	if m != nil {
+		return m.Root
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ContainerPath)(nil), "proto.ksync.ContainerPath")
 	proto.RegisterType((*BasePath)(nil), "proto.ksync.BasePath")
@@ -355,6 +517,9 @@ func init() {
 	proto.RegisterType((*VersionInfo)(nil), "proto.ksync.VersionInfo")
 	proto.RegisterType((*DockerVersion)(nil), "proto.ksync.DockerVersion")
 	proto.RegisterType((*DockerInfo)(nil), "proto.ksync.DockerInfo")
+	proto.RegisterType((*Runtime)(nil), "proto.ksync.Runtime")
+	proto.RegisterType((*RuntimeVersion)(nil), "proto.ksync.RuntimeVersion")
+	proto.RegisterType((*RuntimeInfo)(nil), "proto.ksync.RuntimeInfo")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -375,6 +540,8 @@ type RadarClient interface {
 	GetVersionInfo(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*VersionInfo, error)
 	GetDockerVersion(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*DockerVersion, error)
 	GetDockerInfo(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*DockerInfo, error)
+	GetRuntimeVersion(ctx context.Context, in *Runtime, opts ...grpc.CallOption) (*RuntimeVersion, error)
+	GetRuntimeInfo(ctx context.Context, in *Runtime, opts ...grpc.CallOption) (*RuntimeInfo, error)
 }
 
 type radarClient struct {
@@ -439,6 +606,24 @@ func (c *radarClient) GetDockerInfo(ctx context.Context, in *empty.Empty, opts .
 	return out, nil
 }
 
+func (c *radarClient) GetRuntimeVersion(ctx context.Context, in *Runtime, opts ...grpc.CallOption) (*RuntimeVersion, error) {
+	out := new(RuntimeVersion)
+	err := c.cc.Invoke(ctx, "/proto.ksync.Radar/GetRuntimeVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radarClient) GetRuntimeInfo(ctx context.Context, in *Runtime, opts ...grpc.CallOption) (*RuntimeInfo, error) {
+	out := new(RuntimeInfo)
+	err := c.cc.Invoke(ctx, "/proto.ksync.Radar/GetRuntimeInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Radar service
 
 type RadarServer interface {
@@ -448,6 +633,8 @@ type RadarServer interface {
 	GetVersionInfo(context.Context, *empty.Empty) (*VersionInfo, error)
 	GetDockerVersion(context.Context, *empty.Empty) (*DockerVersion, error)
 	GetDockerInfo(context.Context, *empty.Empty) (*DockerInfo, error)
+	GetRuntimeVersion(context.Context, *Runtime) (*RuntimeVersion, error)
+	GetRuntimeInfo(context.Context, *Runtime) (*RuntimeInfo, error)
 }
 
 func RegisterRadarServer(s *grpc.Server, srv RadarServer) {
@@ -562,6 +749,42 @@ func _Radar_GetDockerInfo_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Radar_GetRuntimeVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Runtime)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadarServer).GetRuntimeVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ksync.Radar/GetRuntimeVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadarServer).GetRuntimeVersion(ctx, req.(*Runtime))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radar_GetRuntimeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Runtime)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadarServer).GetRuntimeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ksync.Radar/GetRuntimeInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadarServer).GetRuntimeInfo(ctx, req.(*Runtime))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Radar_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.ksync.Radar",
 	HandlerType: (*RadarServer)(nil),
@@ -590,43 +813,57 @@ var _Radar_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetDockerInfo",
 			Handler:    _Radar_GetDockerInfo_Handler,
 		},
+		{
+			MethodName: "GetRuntimeVersion",
+			Handler:    _Radar_GetRuntimeVersion_Handler,
+		},
+		{
+			MethodName: "GetRuntimeInfo",
+			Handler:    _Radar_GetRuntimeInfo_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/radar.proto",
 }
 
-func init() { proto.RegisterFile("proto/radar.proto", fileDescriptor_radar_33960b4f5aee43a0) }
-
-var fileDescriptor_radar_33960b4f5aee43a0 = []byte{
-	// 473 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0xcd, 0x77, 0xc8, 0xa4, 0xa9, 0xc2, 0x4a, 0x84, 0x25, 0xa0, 0xaa, 0xac, 0x38, 0xf4, 0xe4,
-	0x48, 0xe5, 0xc8, 0x85, 0xa6, 0xa9, 0x4c, 0x0e, 0xa8, 0x95, 0x8b, 0xb8, 0xa2, 0xad, 0xb3, 0x71,
-	0x56, 0x4d, 0xbc, 0xd5, 0x7a, 0x82, 0x94, 0x9f, 0xc2, 0x85, 0xbf, 0xc4, 0x5f, 0x42, 0xde, 0x8f,
-	0xd4, 0x5b, 0x35, 0x3d, 0x79, 0xe6, 0xbd, 0x99, 0xf1, 0x9b, 0xd9, 0x07, 0xaf, 0x1f, 0xb4, 0x42,
-	0x35, 0xd1, 0x7c, 0xc1, 0x75, 0x64, 0x62, 0xd2, 0x37, 0x9f, 0xe8, 0xbe, 0xd8, 0xe5, 0xe9, 0xf8,
-	0x7d, 0xa6, 0x54, 0xb6, 0x16, 0x13, 0x83, 0xdd, 0x6d, 0x97, 0x13, 0xb1, 0x79, 0xc0, 0x9d, 0xad,
-	0x64, 0xe7, 0x30, 0xb8, 0x54, 0x39, 0x72, 0x99, 0x0b, 0x7d, 0xc3, 0x71, 0x45, 0x3e, 0xc2, 0x51,
-	0xea, 0x81, 0x5f, 0x72, 0x41, 0xeb, 0xa7, 0xf5, 0xb3, 0x5e, 0xd2, 0xdf, 0x63, 0xf3, 0x05, 0x3b,
-	0x81, 0x57, 0x53, 0x5e, 0x08, 0x53, 0x4e, 0xa0, 0xb5, 0xdc, 0xae, 0xd7, 0xae, 0xcc, 0xc4, 0xec,
-	0x1d, 0xb4, 0xaf, 0xb4, 0x56, 0x9a, 0x0c, 0xa1, 0xb9, 0x29, 0x32, 0xc7, 0x95, 0x21, 0xfb, 0x53,
-	0x87, 0xfe, 0x4f, 0xa1, 0x0b, 0xa9, 0xf2, 0x79, 0xbe, 0x54, 0x84, 0x42, 0xd7, 0xa5, 0xae, 0xca,
-	0xa7, 0xe4, 0x03, 0xf4, 0x62, 0xe5, 0xb9, 0x86, 0xe1, 0x1e, 0x01, 0xc3, 0x4a, 0xbc, 0x54, 0x9b,
-	0x8d, 0x44, 0xda, 0x74, 0xac, 0x07, 0xc8, 0x08, 0x3a, 0xb1, 0xc4, 0x1f, 0x3c, 0xa3, 0x2d, 0x43,
-	0xb9, 0xac, 0xec, 0x9a, 0x6e, 0xe5, 0x7a, 0x31, 0xe3, 0x28, 0x68, 0xdb, 0x76, 0xed, 0x01, 0xf6,
-	0xaf, 0x0e, 0x83, 0x99, 0x4a, 0xef, 0x85, 0xf6, 0x7f, 0x39, 0xac, 0xee, 0x04, 0xe0, 0xe2, 0x66,
-	0x1e, 0xca, 0xab, 0x20, 0xe4, 0x13, 0x0c, 0xbe, 0xcb, 0xbc, 0x52, 0x62, 0x35, 0x86, 0x60, 0xb8,
-	0x45, 0xeb, 0xe9, 0x16, 0xc1, 0x05, 0xda, 0x4f, 0x2f, 0x70, 0x0c, 0x8d, 0xeb, 0x82, 0x76, 0x0c,
-	0xdc, 0xb8, 0x2e, 0xca, 0x87, 0xb8, 0xd0, 0xe9, 0x8a, 0x76, 0xed, 0x43, 0x94, 0x31, 0x5b, 0x01,
-	0xd8, 0x85, 0xcc, 0xad, 0x47, 0xd0, 0x99, 0x69, 0xf9, 0x5b, 0x68, 0xb7, 0x8c, 0xcb, 0x08, 0x83,
-	0x23, 0x1b, 0xdd, 0x22, 0xc7, 0x6d, 0x41, 0x1b, 0xa7, 0xcd, 0xb3, 0x5e, 0x12, 0x60, 0xe5, 0xbe,
-	0x76, 0x52, 0xa2, 0x94, 0x3f, 0x78, 0x05, 0x39, 0xff, 0xdb, 0x84, 0x76, 0x52, 0x1a, 0x90, 0x4c,
-	0xa1, 0x1f, 0x0b, 0xdc, 0xfb, 0x63, 0x1c, 0x55, 0xac, 0x18, 0x05, 0x56, 0x1b, 0xbf, 0x09, 0x38,
-	0xdf, 0xc2, 0x6a, 0xe4, 0x2b, 0x0c, 0x13, 0x51, 0x20, 0xd7, 0x78, 0xbb, 0xcb, 0x53, 0x5c, 0xc9,
-	0x3c, 0x23, 0xa3, 0xc8, 0xda, 0x38, 0xf2, 0x36, 0x8e, 0xae, 0x4a, 0x1b, 0x8f, 0x49, 0x30, 0xc4,
-	0xf8, 0x8e, 0xd5, 0xc8, 0x17, 0xe8, 0xba, 0x09, 0x2f, 0x2a, 0x78, 0xbe, 0x79, 0x06, 0xc7, 0xb1,
-	0xc0, 0xaa, 0x4d, 0x0f, 0xfd, 0x9c, 0x06, 0xfd, 0x95, 0x0e, 0x56, 0x23, 0xdf, 0x60, 0x18, 0x0b,
-	0x0c, 0x0d, 0x75, 0x68, 0x4e, 0xa8, 0x31, 0xe8, 0x61, 0x35, 0x32, 0x85, 0xc1, 0x7e, 0xd2, 0x8b,
-	0x72, 0xde, 0x3e, 0x33, 0xc6, 0xaa, 0xb9, 0xeb, 0x18, 0xe6, 0xf3, 0xff, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x68, 0x58, 0xa3, 0xbc, 0x2d, 0x04, 0x00, 0x00,
+func init() { proto.RegisterFile("proto/radar.proto", fileDescriptor_radar_c41d305e714fd013) }
+
+var fileDescriptor_radar_c41d305e714fd013 = []byte{
+	// 572 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x53, 0xdd, 0x6e, 0xda, 0x4c,
+	0x10, 0x05, 0x63, 0xe0, 0xcb, 0x10, 0x10, 0x59, 0x7d, 0xa5, 0x5b, 0xd2, 0xa2, 0x74, 0xd5, 0x8b,
+	0x5c, 0x19, 0xa9, 0xbd, 0xec, 0x4d, 0x43, 0x88, 0x5c, 0xa4, 0xb6, 0x89, 0x9c, 0xaa, 0x97, 0xad,
+	0x1c, 0x58, 0x60, 0x15, 0xec, 0x4d, 0xd7, 0x4b, 0x25, 0x1e, 0xa5, 0xef, 0xd0, 0x77, 0xe9, 0x2b,
+	0x55, 0xde, 0x1f, 0xf0, 0x22, 0xc3, 0x95, 0x67, 0xcf, 0xcc, 0x1c, 0x9f, 0x99, 0xdd, 0x03, 0x67,
+	0x4f, 0x82, 0x4b, 0x3e, 0x14, 0xf1, 0x2c, 0x16, 0x81, 0x8a, 0x51, 0x4b, 0x7d, 0x82, 0xc7, 0x6c,
+	0x93, 0x4e, 0xfb, 0xe7, 0x0b, 0xce, 0x17, 0x2b, 0x3a, 0x54, 0xd8, 0xc3, 0x7a, 0x3e, 0xa4, 0xc9,
+	0x93, 0xdc, 0xe8, 0x4a, 0xf2, 0x09, 0xda, 0xd7, 0x3c, 0x95, 0x31, 0x4b, 0xa9, 0xb8, 0x8b, 0xe5,
+	0x12, 0xbd, 0x86, 0xd3, 0xa9, 0x05, 0x7e, 0xb0, 0x19, 0xae, 0x5e, 0x54, 0x2f, 0x4f, 0xa2, 0xd6,
+	0x16, 0x9b, 0xcc, 0x10, 0x86, 0xa6, 0x58, 0xa7, 0x92, 0x25, 0x14, 0x7b, 0x2a, 0x6b, 0x8f, 0x64,
+	0x00, 0xff, 0x8d, 0xe2, 0x8c, 0x2a, 0x22, 0x04, 0xfe, 0x7c, 0xbd, 0x5a, 0x19, 0x02, 0x15, 0x93,
+	0x17, 0x50, 0xbf, 0x11, 0x82, 0x0b, 0xd4, 0x85, 0x5a, 0x92, 0x2d, 0x4c, 0x2e, 0x0f, 0xc9, 0xef,
+	0x2a, 0xb4, 0xbe, 0x51, 0x91, 0x31, 0x9e, 0x4e, 0xd2, 0x39, 0xcf, 0x7f, 0x62, 0x8e, 0xa6, 0xca,
+	0x1e, 0xd1, 0x4b, 0x38, 0x09, 0xb9, 0xcd, 0x69, 0x01, 0x3b, 0x40, 0x65, 0x99, 0xbc, 0xe6, 0x49,
+	0xc2, 0x24, 0xae, 0x99, 0xac, 0x05, 0x50, 0x0f, 0x1a, 0x21, 0x93, 0x5f, 0xe3, 0x05, 0xf6, 0x55,
+	0xca, 0x9c, 0xf2, 0xae, 0xd1, 0x9a, 0xad, 0x66, 0xe3, 0x58, 0x52, 0x5c, 0xd7, 0x5d, 0x5b, 0x80,
+	0xfc, 0xad, 0x42, 0x7b, 0xcc, 0xa7, 0x8f, 0x54, 0xd8, 0xbf, 0x1c, 0x56, 0x37, 0x00, 0xb8, 0xba,
+	0x9b, 0xb8, 0xf2, 0x0a, 0x08, 0x7a, 0x03, 0xed, 0xcf, 0x2c, 0x2d, 0x94, 0x68, 0x8d, 0x2e, 0xe8,
+	0x4e, 0xe1, 0xef, 0x4f, 0xe1, 0x6c, 0xa0, 0xbe, 0xbf, 0x81, 0x0e, 0x78, 0xb7, 0x19, 0x6e, 0x28,
+	0xd8, 0xbb, 0xcd, 0xf2, 0x8b, 0xb8, 0x12, 0xd3, 0x25, 0x6e, 0xea, 0x8b, 0xc8, 0x63, 0xb2, 0x04,
+	0xd0, 0x03, 0xa9, 0x5d, 0xf7, 0xa0, 0x31, 0x16, 0xec, 0x17, 0x15, 0x66, 0x18, 0x73, 0x42, 0x04,
+	0x4e, 0x75, 0x74, 0x2f, 0x63, 0xb9, 0xce, 0xb0, 0x77, 0x51, 0xbb, 0x3c, 0x89, 0x1c, 0x0c, 0x0d,
+	0x2c, 0x53, 0xc4, 0xb9, 0x5d, 0x78, 0x01, 0x21, 0xaf, 0xa0, 0x19, 0xe9, 0xd7, 0x91, 0x0b, 0x49,
+	0xe3, 0x84, 0xda, 0x17, 0x91, 0xc7, 0xe4, 0x3b, 0x74, 0x4c, 0xda, 0xca, 0x47, 0xe0, 0x7f, 0x29,
+	0x54, 0xe5, 0x71, 0x71, 0xdd, 0xde, 0xb1, 0x75, 0xd7, 0xf6, 0xd7, 0x4d, 0x7e, 0x42, 0xcb, 0xf0,
+	0xab, 0x49, 0xcb, 0xc8, 0x77, 0xd3, 0x7b, 0x47, 0xa7, 0xaf, 0x95, 0x4c, 0x8f, 0xc0, 0x57, 0x73,
+	0xeb, 0x2b, 0x52, 0xf1, 0xdb, 0x3f, 0x3e, 0xd4, 0xa3, 0xdc, 0x8c, 0x68, 0x04, 0xad, 0x90, 0xca,
+	0xad, 0x23, 0xfa, 0x41, 0xc1, 0x96, 0x81, 0x63, 0xbb, 0xfe, 0x33, 0x27, 0x67, 0x5b, 0x48, 0x05,
+	0x7d, 0x80, 0x6e, 0x44, 0x33, 0x19, 0x0b, 0x79, 0xbf, 0x49, 0xa7, 0x72, 0xc9, 0xd2, 0x05, 0xea,
+	0x05, 0xda, 0xd2, 0x81, 0xb5, 0x74, 0x70, 0x93, 0x5b, 0xba, 0x8f, 0x1c, 0x12, 0xe5, 0x34, 0x52,
+	0x41, 0xef, 0xa1, 0x69, 0x18, 0x8e, 0x2a, 0x28, 0x6f, 0x1e, 0x43, 0x27, 0xa4, 0xb2, 0x68, 0xcc,
+	0x43, 0x3f, 0xc7, 0x4e, 0x7f, 0xa1, 0x83, 0x54, 0xd0, 0x47, 0xe8, 0x86, 0x54, 0xba, 0x16, 0x3a,
+	0xc4, 0xe3, 0x6a, 0x74, 0x7a, 0x48, 0x05, 0x8d, 0xa0, 0xbd, 0x65, 0x3a, 0x2a, 0xe7, 0x79, 0x09,
+	0xcd, 0x56, 0xcd, 0x59, 0x48, 0xe5, 0xde, 0xb3, 0xfb, 0xdf, 0xa9, 0x37, 0xc9, 0xfe, 0x79, 0x19,
+	0x5a, 0x54, 0xd3, 0xd9, 0x31, 0x29, 0x39, 0xe5, 0x34, 0xb8, 0x0c, 0xd5, 0x6a, 0x1e, 0x1a, 0x2a,
+	0xf5, 0xee, 0xdf, 0x00, 0xc2, 0x7a, 0xea, 0x8b, 0xc7, 0x05, 0x00, 0x00,
 }
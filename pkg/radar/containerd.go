@@ -0,0 +1,95 @@
+package radar
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+
+	pb "github.com/ksync/ksync/pkg/proto"
+)
+
+// All containers started by the kubelet live in this containerd namespace.
+var containerdNamespace = "k8s.io"
+
+// The v1 shim keeps its bundles in a different directory than the v2 shims.
+var containerdV1Runtime = "io.containerd.runtime.v1.linux"
+
+// containerdRuntime uses containerd's built in CRI plugin. Only finding the
+// root filesystem and the storage configuration differ from CRI-O.
+type containerdRuntime struct {
+	*criRuntime
+
+	root string
+}
+
+func newContainerdRuntime() (Runtime, error) {
+	cri, err := newCRIClient(
+		ContainerdRuntime, viper.GetString("containerd-socket"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &containerdRuntime{
+		criRuntime: cri,
+		root:       viper.GetString("containerd-root"),
+	}, nil
+}
+
+// Containerd mounts the root filesystem inside of the task's bundle, which
+// lives in the state directory. The runtime spec only has a relative path.
+func (c *containerdRuntime) RootPath(ctx context.Context, id string) (string, error) {
+	info, err := c.containerInfo(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	bundle := "io.containerd.runtime.v2.task"
+	if info.RuntimeType == containerdV1Runtime {
+		bundle = containerdV1Runtime
+	}
+
+	log.WithFields(log.Fields{
+		"id":      id,
+		"runtime": info.RuntimeType,
+	}).Debug("root path retrieved")
+
+	return filepath.Join(
+		c.root, bundle, containerdNamespace, id, "rootfs"), nil
+}
+
+// Info asks the CRI plugin for its configuration as that is what decides the
+// snapshotter kubernetes containers use.
+func (c *containerdRuntime) Info(ctx context.Context) (*pb.RuntimeInfo, error) {
+	status, err := c.client.Status(ctx, &runtimeapi.StatusRequest{Verbose: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Containerd struct {
+			Snapshotter string `json:"snapshotter"`
+		} `json:"containerd"`
+		ContainerdRootDir string `json:"containerdRootDir"`
+		StateDir          string `json:"stateDir"`
+	}
+
+	if err := json.Unmarshal([]byte(status.Info["config"]), &config); err != nil {
+		return nil, err
+	}
+
+	return &pb.RuntimeInfo{
+		Name:   ContainerdRuntime,
+		Driver: config.Containerd.Snapshotter,
+		DriverStatus: []string{
+			fmt.Sprintf("Root Dir: %s", config.ContainerdRootDir),
+		},
+		// The CRI plugin's state lives inside of containerd's state directory,
+		// which is where the task bundles are.
+		Root: filepath.Dir(config.StateDir),
+	}, nil
+}
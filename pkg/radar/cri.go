@@ -0,0 +1,214 @@
+package radar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+
+	pb "github.com/ksync/ksync/pkg/proto"
+)
+
+// defaultStopTimeout is how long containers get to stop when no timeout is
+// given, the same as docker's default.
+var defaultStopTimeout = 10 * time.Second
+
+// criRuntime talks to a runtime over the kubelet's CRI API. It is used directly
+// for CRI-O and is the base for containerd.
+type criRuntime struct {
+	name   string
+	socket string
+	conn   *grpc.ClientConn
+	client runtimeapi.RuntimeServiceClient
+}
+
+func dialUnix(ctx context.Context, addr string) (net.Conn, error) {
+	return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+}
+
+func newCRIClient(name string, socket string) (*criRuntime, error) {
+	// Dialing blocks until the timeout, bail early on nodes that aren't running
+	// this runtime.
+	if _, err := os.Stat(socket); err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(
+		socket,
+		grpc.WithTimeout(5*time.Second), // nolint: staticcheck
+		grpc.WithBlock(),
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(dialUnix))
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"runtime": name,
+		"socket":  socket,
+	}).Debug("cri client created")
+
+	return &criRuntime{
+		name:   name,
+		socket: socket,
+		conn:   conn,
+		client: runtimeapi.NewRuntimeServiceClient(conn),
+	}, nil
+}
+
+func newCRIRuntime() (Runtime, error) {
+	return newCRIClient(CRIORuntime, viper.GetString("crio-socket"))
+}
+
+// containerInfo is the subset of the verbose container status that radar uses.
+type containerInfo struct {
+	RuntimeType string `json:"runtimeType"`
+	RuntimeSpec struct {
+		Root struct {
+			Path string `json:"path"`
+		} `json:"root"`
+	} `json:"runtimeSpec"`
+}
+
+func (c *criRuntime) containerInfo(
+	ctx context.Context, id string) (*containerInfo, error) {
+
+	status, err := c.client.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{
+		ContainerId: id,
+		Verbose:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var info containerInfo
+	if err := json.Unmarshal([]byte(status.Info["info"]), &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// The runtime spec is part of the verbose container status and contains the
+// mounted root filesystem for the container.
+func (c *criRuntime) RootPath(ctx context.Context, id string) (string, error) {
+	info, err := c.containerInfo(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if info.RuntimeSpec.Root.Path == "" {
+		return "", fmt.Errorf("no root path for container: %s", id)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("root path retrieved")
+
+	return info.RuntimeSpec.Root.Path, nil
+}
+
+// Restart stops the container. There is no restart in the CRI, instead the
+// kubelet will start a new container to replace the stopped one.
+func (c *criRuntime) Restart(
+	ctx context.Context, id string, timeout *time.Duration) error {
+
+	if timeout == nil {
+		timeout = &defaultStopTimeout
+	}
+
+	_, err := c.client.StopContainer(ctx, &runtimeapi.StopContainerRequest{
+		ContainerId: id,
+		Timeout:     int64(timeout.Seconds()),
+	})
+
+	return err
+}
+
+func (c *criRuntime) ContainerID(
+	ctx context.Context, podName, containerName string) (string, error) {
+
+	resp, err := c.client.ListContainers(ctx, &runtimeapi.ListContainersRequest{
+		Filter: &runtimeapi.ContainerFilter{
+			State: &runtimeapi.ContainerStateValue{
+				State: runtimeapi.ContainerState_CONTAINER_RUNNING,
+			},
+			LabelSelector: map[string]string{
+				"io.kubernetes.pod.name":       podName,
+				"io.kubernetes.container.name": containerName,
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.Containers) == 0 {
+		return "", fmt.Errorf("could not find for pod: %s", podName)
+	}
+
+	return resp.Containers[0].Id, nil
+}
+
+func (c *criRuntime) Version(ctx context.Context) (*pb.RuntimeVersion, error) {
+	version, err := c.client.Version(ctx, &runtimeapi.VersionRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RuntimeVersion{
+		Name:       c.name,
+		Version:    version.RuntimeVersion,
+		APIVersion: version.RuntimeApiVersion,
+	}, nil
+}
+
+// Info uses CRI-O's own info endpoint as storage configuration is not part of
+// the CRI.
+func (c *criRuntime) Info(ctx context.Context) (*pb.RuntimeInfo, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialUnix(ctx, c.socket)
+			},
+		},
+		Timeout: 5 * time.Second,
+	}
+
+	resp, err := client.Get("http://crio/info")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	var info struct {
+		StorageDriver string `json:"storage_driver"`
+		StorageRoot   string `json:"storage_root"`
+		CgroupDriver  string `json:"cgroup_driver"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+
+	return &pb.RuntimeInfo{
+		Name:   CRIORuntime,
+		Driver: info.StorageDriver,
+		DriverStatus: []string{
+			fmt.Sprintf("Cgroup Driver: %s", info.CgroupDriver),
+		},
+		Root: info.StorageRoot,
+	}, nil
+}
+
+func (c *criRuntime) Close() error {
+	return c.conn.Close()
+}
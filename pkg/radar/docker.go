@@ -2,7 +2,10 @@ package radar
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/golang/protobuf/ptypes/empty"
 	log "github.com/sirupsen/logrus"
@@ -11,44 +14,153 @@ import (
 	pb "github.com/ksync/ksync/pkg/proto"
 )
 
+type dockerRuntime struct {
+	client *client.Client
+}
+
+func newDockerClient() (*client.Client, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	cli.NegotiateAPIVersion(context.Background())
+
+	log.Debug("docker client created")
+
+	return cli, nil
+}
+
+func newDockerRuntime() (Runtime, error) {
+	cli, err := newDockerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &dockerRuntime{client: cli}, nil
+}
+
 // TODO: needs to be able to reference volumes
 // TODO: what to do about paths that include volumes? two syncs? they're different
 // directories on the host itself. Maybe an alert for v1?
-func getRootPath(containerPath *pb.ContainerPath) (string, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv)
+func (d *dockerRuntime) RootPath(ctx context.Context, id string) (string, error) {
+	cntr, err := d.client.ContainerInspect(ctx, id)
 	if err != nil {
 		return "", err
 	}
 
-	cli.NegotiateAPIVersion(context.Background())
+	info, err := d.client.Info(ctx)
+	if err != nil {
+		return "", err
+	}
 
-	log.Debug("docker client created")
+	storage := &dockerStorage{
+		root:     info.DockerRootDir,
+		rootless: isRootless(info),
+	}
+
+	path, err := storage.rootPath(&cntr)
+	if err != nil {
+		return "", err
+	}
+
+	log.WithFields(log.Fields{
+		"name":   cntr.Name,
+		"id":     id,
+		"driver": cntr.GraphDriver.Name,
+	}).Debug("root path retrieved")
+
+	return path, nil
+}
+
+// Restart is an effective "hot reload" because docker restarts and keeps the
+// overlayfs in place (we're still putting files into it).
+func (d *dockerRuntime) Restart(
+	ctx context.Context, id string, timeout *time.Duration) error {
+
+	return d.client.ContainerRestart(ctx, id, timeout)
+}
 
-	cntr, err := cli.ContainerInspect(
-		context.Background(), containerPath.ContainerId)
+func (d *dockerRuntime) ContainerID(
+	ctx context.Context, podName, containerName string) (string, error) {
+
+	args := filters.NewArgs()
+	args.Add("label", fmt.Sprintf(
+		"io.kubernetes.container.name=%s", containerName))
+	args.Add("label", fmt.Sprintf("io.kubernetes.pod.name=%s", podName))
+
+	cntrs, err := d.client.ContainerList(
+		ctx,
+		types.ContainerListOptions{
+			Filters: args,
+		},
+	)
 	if err != nil {
 		return "", err
 	}
 
+	if len(cntrs) == 0 {
+		return "", fmt.Errorf("could not find for pod: %s", podName)
+	}
+
 	log.WithFields(log.Fields{
-		"name": cntr.Name,
-		"id":   containerPath.ContainerId,
-	}).Debug("merge path retrieved")
+		"pod":    podName,
+		"id":     cntrs[0].ID,
+		"status": cntrs[0].Status,
+		"state":  cntrs[0].State,
+	}).Debug("found container")
 
-	// TODO: how does this work on systems not running overlay2? Will need to
-	// select on type.
-	return cntr.GraphDriver.Data["MergedDir"], nil
+	return cntrs[0].ID, nil
+}
+
+func (d *dockerRuntime) Version(ctx context.Context) (*pb.RuntimeVersion, error) {
+	info, err := d.client.ServerVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RuntimeVersion{
+		Name:       DockerRuntime,
+		Version:    info.Version,
+		APIVersion: info.APIVersion,
+	}, nil
+}
+
+func (d *dockerRuntime) Info(ctx context.Context) (*pb.RuntimeInfo, error) {
+	info, err := d.client.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := []string{}
+	for _, pair := range info.DriverStatus {
+		status = append(status, fmt.Sprintf("%s: %s", pair[0], pair[1]))
+	}
+
+	if isRootless(info) {
+		status = append(status, "Rootless: true")
+	}
+
+	return &pb.RuntimeInfo{
+		Name:         DockerRuntime,
+		Driver:       info.Driver,
+		DriverStatus: status,
+		Root:         info.DockerRootDir,
+	}, nil
+}
+
+func (d *dockerRuntime) Close() error {
+	return d.client.Close()
 }
 
 func (r *radarServer) GetDockerVersion(
 	ctx context.Context, _ *empty.Empty) (*pb.DockerVersion, error) {
 
-	client, err := client.NewClientWithOpts(client.FromEnv)
+	client, err := newDockerClient()
 	if err != nil {
 		return nil, err
 	}
-
-	client.NegotiateAPIVersion(context.Background())
+	defer client.Close() // nolint: errcheck
 
 	info, err := client.ServerVersion(context.Background())
 	if err != nil {
@@ -69,26 +181,20 @@ func (r *radarServer) GetDockerVersion(
 func (r *radarServer) GetDockerInfo(
 	ctx context.Context, _ *empty.Empty) (*pb.DockerInfo, error) {
 
-	client, err := client.NewClientWithOpts(client.FromEnv)
+	runtime, err := newDockerRuntime()
 	if err != nil {
 		return nil, err
 	}
+	defer runtime.Close() // nolint: errcheck
 
-	client.NegotiateAPIVersion(context.Background())
-
-	info, err := client.Info(context.Background())
+	info, err := runtime.Info(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	status := []string{}
-	for _, pair := range info.DriverStatus {
-		status = append(status, fmt.Sprintf("%s: %s", pair[0], pair[1]))
-	}
-
 	return &pb.DockerInfo{
 		Driver:       info.Driver,
-		DriverStatus: status,
-		DockerRoot:   info.DockerRootDir,
+		DriverStatus: info.DriverStatus,
+		DockerRoot:   info.Root,
 	}, nil
 }
@@ -14,7 +14,13 @@ func (r *radarServer) GetBasePath(
 	ctx context.Context,
 	containerPath *pb.ContainerPath) (*pb.BasePath, error) {
 
-	rootPath, err := getRootPath(containerPath)
+	runtime, err := NewRuntime(containerPath.Runtime)
+	if err != nil {
+		return nil, err
+	}
+	defer runtime.Close() // nolint: errcheck
+
+	rootPath, err := runtime.RootPath(ctx, containerPath.ContainerId)
 	if err != nil {
 		return nil, err
 	}
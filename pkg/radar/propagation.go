@@ -0,0 +1,71 @@
+package radar
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var mountInfoPath = "/proc/self/mountinfo"
+
+// isPropagated looks for the mount at path in a mountinfo table and checks
+// whether it receives mounts from the host. Mounts created with HostToContainer
+// propagation are slaves of the host's mount and have a `master:N` tag in
+// their optional fields. See proc(5) for the format.
+func isPropagated(mountInfo io.Reader, path string) bool {
+	path = filepath.Clean(path)
+	propagated := false
+
+	scanner := bufio.NewScanner(mountInfo)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 || fields[4] != path {
+			continue
+		}
+
+		// Later mounts on the same mount point hide earlier ones, the last entry
+		// wins.
+		propagated = false
+		for _, field := range fields[6:] {
+			if field == "-" {
+				break
+			}
+
+			if strings.HasPrefix(field, "master:") {
+				propagated = true
+			}
+		}
+	}
+
+	return propagated
+}
+
+// mountsPropagated checks whether any of the runtime roots mounted into this
+// pod receive new mounts from the host. The kernel and runtime either support
+// propagation for every hostPath volume or for none, so one is enough.
+func mountsPropagated(paths ...string) bool {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		file, err := os.Open(mountInfoPath)
+		if err != nil {
+			log.Debug(err)
+			return false
+		}
+
+		propagated := isPropagated(file, path)
+		file.Close() // nolint: errcheck, gosec
+
+		if propagated {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,23 @@
+package radar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testMountInfo = `2203 1901 0:190 / / rw,relatime master:616 - overlay overlay rw,lowerdir=/var/lib/docker/overlay2/l/ABC
+2210 2203 8:1 /var/lib/docker /var/lib/docker ro,relatime master:1 - ext4 /dev/sda1 rw
+2211 2203 8:1 /run/containerd /run/containerd rw,relatime - ext4 /dev/sda1 rw
+2212 2203 0:24 /docker.sock /var/run/docker.sock rw,nosuid,nodev - tmpfs tmpfs rw
+`
+
+func TestIsPropagated(t *testing.T) {
+	assert.True(t, isPropagated(
+		strings.NewReader(testMountInfo), "/var/lib/docker/"))
+	assert.False(t, isPropagated(
+		strings.NewReader(testMountInfo), "/run/containerd"))
+	assert.False(t, isPropagated(
+		strings.NewReader(testMountInfo), "/var/lib/kubelet"))
+}
@@ -1,12 +1,8 @@
 package radar
 
 import (
-	"fmt"
 	"time"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
-	apiclient "github.com/docker/docker/client"
 	"github.com/golang/protobuf/ptypes/empty"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
@@ -18,90 +14,76 @@ import (
 
 // RestartSyncthing restarts the syncthing sidecar to this radar process. This is
 // needed because:
-//     When a container is started, it inherits the mount table. The mounts
-//     are maintained internally. Any new mounts do not show up inside the
-//     container. The implication of this is that any new containers starting
-//     after syncthing will not have their FS mounted inside syncthing's container.
-//     While the files are all available, the actual mount will not occur.
-//     As syncthing clients will just reconnect after loosing connection with the
-//     server, we restart syncthing to refresh the mounts on demand.
+//
+//	When a container is started, it inherits the mount table. The mounts
+//	are maintained internally. Any new mounts do not show up inside the
+//	container. The implication of this is that any new containers starting
+//	after syncthing will not have their FS mounted inside syncthing's container.
+//	While the files are all available, the actual mount will not occur.
+//	As syncthing clients will just reconnect after loosing connection with the
+//	server, we restart syncthing to refresh the mounts on demand.
+//
+// The runtime roots are mounted with HostToContainer propagation. When the
+// kernel and runtime support it, new mounts show up in syncthing's container
+// as they happen and the restart is skipped.
 func (r *radarServer) RestartSyncthing(
 	ctx context.Context, _ *empty.Empty) (*pb.Error, error) {
 
 	// TODO: this is awful, I can't figure out how to attach config to context.
 	podName := viper.GetString("pod-name")
 
-	client, err := apiclient.NewClientWithOpts(apiclient.FromEnv)
-	if err != nil {
-		return nil, err
-	}
+	if mountsPropagated(
+		viper.GetString("docker-root"), viper.GetString("containerd-root")) {
 
-	client.NegotiateAPIVersion(context.Background())
+		log.WithFields(log.Fields{
+			"pod": podName,
+		}).Debug("mounts are propagated, skipping syncthing restart")
 
-	args := filters.NewArgs()
-	args.Add("label", "io.kubernetes.container.name=syncthing")
-	args.Add("label", fmt.Sprintf("io.kubernetes.pod.name=%s", podName))
-
-	cntrs, err := client.ContainerList(
-		context.Background(),
-		types.ContainerListOptions{
-			Filters: args,
-		},
-	)
+		return &pb.Error{Msg: ""}, nil
+	}
 
+	// The sidecar runs in whatever runtime the node is using, so every runtime
+	// is searched for it.
+	runtime, id, err := findContainer(context.Background(), podName, "syncthing")
 	if err != nil {
 		return nil, err
 	}
+	defer runtime.Close() // nolint: errcheck
 
-	if len(cntrs) == 0 {
-		return nil, fmt.Errorf("could not find for pod: %s", podName)
-	}
-
-	cntr := cntrs[0]
-
-	log.WithFields(log.Fields{
-		"pod":    podName,
-		"id":     cntr.ID,
-		"status": cntr.Status,
-		"state":  cntr.State,
-	}).Debug("found syncthing container")
-
-	if err := client.ContainerRestart(
-		context.Background(), cntr.ID, nil); err != nil {
+	// Syncthing gets to shut down cleanly, it saves its index on the way.
+	if err := runtime.Restart(context.Background(), id, nil); err != nil {
 		return nil, err
 	}
 
 	log.WithFields(log.Fields{
-		"pod":    podName,
-		"id":     cntr.ID,
-		"status": cntr.Status,
-		"state":  cntr.State,
+		"pod": podName,
+		"id":  id,
 	}).Debug("restarted syncthing container")
 
 	return &pb.Error{Msg: ""}, nil
 }
 
-// Restart restarts a local container. This is an effective "hot reload" because
-// docker restarts and keeps the overlayfs in place (we're still putting files
-// into it)
+// Restart restarts a local container. For docker, this is an effective "hot
+// reload" because docker restarts and keeps the overlayfs in place (we're still
+// putting files into it). Other runtimes replace the container.
 func (r *radarServer) Restart(
 	ctx context.Context, cntr *pb.ContainerPath) (*pb.Error, error) {
 
-	client, err := apiclient.NewClientWithOpts(apiclient.FromEnv)
+	runtime, err := NewRuntime(cntr.Runtime)
 	if err != nil {
 		return nil, err
 	}
-
-	client.NegotiateAPIVersion(context.Background())
+	defer runtime.Close() // nolint: errcheck
 
 	timeout := 0 * time.Second
-	if err := client.ContainerRestart(
+	if err := runtime.Restart(
 		context.Background(), cntr.ContainerId, &timeout); err != nil {
 		return nil, err
 	}
 
 	log.WithFields(log.Fields{
-		"id": cntr.ContainerId,
+		"id":      cntr.ContainerId,
+		"runtime": cntr.Runtime,
 	}).Debug("restarted container")
 
 	return &pb.Error{Msg: ""}, nil
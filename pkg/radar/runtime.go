@@ -0,0 +1,127 @@
+package radar
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	pb "github.com/ksync/ksync/pkg/proto"
+)
+
+// The container runtimes that radar knows how to talk to. These match the
+// scheme kubernetes puts in front of container IDs (eg. `containerd://<id>`).
+const (
+	DockerRuntime     = "docker"
+	ContainerdRuntime = "containerd"
+	CRIORuntime       = "cri-o"
+)
+
+// Runtime is a container runtime running on the local node. It is used to find
+// where a container's filesystem lives on the host and to control containers.
+type Runtime interface {
+	// RootPath returns the host path for the root filesystem of a container.
+	RootPath(ctx context.Context, id string) (string, error)
+	// Restart restarts a container. The container is given timeout to stop,
+	// nil is the runtime's default.
+	Restart(ctx context.Context, id string, timeout *time.Duration) error
+	// ContainerID finds the running container for a pod and container name.
+	ContainerID(ctx context.Context, podName, containerName string) (string, error)
+	// Version returns the version of the runtime.
+	Version(ctx context.Context) (*pb.RuntimeVersion, error)
+	// Info returns the storage configuration of the runtime.
+	Info(ctx context.Context) (*pb.RuntimeInfo, error)
+	// Close cleans up the connection to the runtime.
+	Close() error
+}
+
+var runtimes = map[string]func() (Runtime, error){
+	DockerRuntime:     newDockerRuntime,
+	ContainerdRuntime: newContainerdRuntime,
+	CRIORuntime:       newCRIRuntime,
+}
+
+// runtimeOrder is the order runtimes are tried in when the runtime hasn't been
+// specified.
+var runtimeOrder = []string{DockerRuntime, ContainerdRuntime, CRIORuntime}
+
+// NewRuntime returns a client for the named runtime. An empty name is treated
+// as docker to stay compatible with clients that do not send a runtime.
+func NewRuntime(name string) (Runtime, error) {
+	if name == "" {
+		name = DockerRuntime
+	}
+
+	newFn, ok := runtimes[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported container runtime: %s", name)
+	}
+
+	return newFn()
+}
+
+// findContainer looks through every runtime available on this node for the
+// container that matches pod and container name. The runtime the container was
+// found in is returned along with the container's id. It is up to the caller
+// to close the runtime.
+func findContainer(
+	ctx context.Context, podName, containerName string) (Runtime, string, error) {
+
+	for _, name := range runtimeOrder {
+		runtime, err := NewRuntime(name)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"runtime": name,
+			}).Debug(err)
+			continue
+		}
+
+		id, err := runtime.ContainerID(ctx, podName, containerName)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"runtime": name,
+			}).Debug(err)
+			runtime.Close() // nolint: errcheck, gosec
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"runtime": name,
+			"pod":     podName,
+			"id":      id,
+		}).Debug("found container")
+
+		return runtime, id, nil
+	}
+
+	return nil, "", fmt.Errorf(
+		"could not find %s for pod: %s", containerName, podName)
+}
+
+// GetRuntimeVersion returns the version of the requested container runtime.
+func (r *radarServer) GetRuntimeVersion(
+	ctx context.Context, rt *pb.Runtime) (*pb.RuntimeVersion, error) {
+
+	runtime, err := NewRuntime(rt.GetName())
+	if err != nil {
+		return nil, err
+	}
+	defer runtime.Close() // nolint: errcheck
+
+	return runtime.Version(ctx)
+}
+
+// GetRuntimeInfo returns the storage configuration of the requested container
+// runtime.
+func (r *radarServer) GetRuntimeInfo(
+	ctx context.Context, rt *pb.Runtime) (*pb.RuntimeInfo, error) {
+
+	runtime, err := NewRuntime(rt.GetName())
+	if err != nil {
+		return nil, err
+	}
+	defer runtime.Close() // nolint: errcheck
+
+	return runtime.Info(ctx)
+}
@@ -0,0 +1,14 @@
+package radar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRuntime(t *testing.T) {
+	runtime, err := NewRuntime("rkt")
+
+	assert.Error(t, err)
+	assert.Nil(t, runtime)
+}
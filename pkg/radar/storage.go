@@ -0,0 +1,115 @@
+package radar
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// dockerStorage resolves where a container's root filesystem is mounted on the
+// host. Each storage driver reports different things in the inspect output, so
+// the lookup is selected on the driver name.
+type dockerStorage struct {
+	// root is docker's root directory (eg. /var/lib/docker).
+	root string
+	// rootless is set when dockerd is running inside of RootlessKit.
+	rootless bool
+}
+
+var errRootless = fmt.Errorf(
+	"rootless docker is not supported, its containers are not visible from the host")
+
+var dockerRootPaths = map[string]func(*dockerStorage, *types.ContainerJSON) (string, error){
+	"overlay":        (*dockerStorage).overlayPath,
+	"overlay2":       (*dockerStorage).overlayPath,
+	"fuse-overlayfs": (*dockerStorage).overlayPath,
+	"btrfs":          (*dockerStorage).btrfsPath,
+	"zfs":            (*dockerStorage).zfsPath,
+	"devicemapper":   (*dockerStorage).devicemapperPath,
+}
+
+// Rootless daemons mount containers inside of RootlessKit's mount namespace
+// and keep their data root in the user's home directory, neither is visible
+// to the DaemonSet. The overlay's upper directory is on the host, but writing
+// to it while it is mounted is undefined and lower layers aren't in it.
+func (s *dockerStorage) rootPath(cntr *types.ContainerJSON) (string, error) {
+	if s.rootless {
+		return "", errRootless
+	}
+
+	if cntr.GraphDriver.Name == "" {
+		return "", fmt.Errorf("no storage driver reported for container: %s", cntr.ID)
+	}
+
+	pathFn, ok := dockerRootPaths[cntr.GraphDriver.Name]
+	if !ok {
+		return "", fmt.Errorf(
+			"unsupported storage driver: %s", cntr.GraphDriver.Name)
+	}
+
+	return pathFn(s, cntr)
+}
+
+func graphData(cntr *types.ContainerJSON, key string) (string, error) {
+	val := cntr.GraphDriver.Data[key]
+	if val == "" {
+		return "", fmt.Errorf(
+			"storage driver %s did not report %s for container: %s",
+			cntr.GraphDriver.Name, key, cntr.ID)
+	}
+
+	return val, nil
+}
+
+// The overlay drivers expose the merged mount directly.
+func (s *dockerStorage) overlayPath(cntr *types.ContainerJSON) (string, error) {
+	return graphData(cntr, "MergedDir")
+}
+
+// Btrfs does not report anything about the container in the inspect output.
+// The subvolume is named after the layer's mount id, which docker only keeps
+// in its layer database.
+func (s *dockerStorage) btrfsPath(cntr *types.ContainerJSON) (string, error) {
+	mountID, err := ioutil.ReadFile(filepath.Join(
+		s.root, "image", "btrfs", "layerdb", "mounts", cntr.ID, "mount-id"))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(
+		s.root, "btrfs", "subvolumes", strings.TrimSpace(string(mountID))), nil
+}
+
+func (s *dockerStorage) zfsPath(cntr *types.ContainerJSON) (string, error) {
+	return graphData(cntr, "Mountpoint")
+}
+
+// The device name is made up of the pool's prefix and the layer's mount id
+// (eg. docker-253:1-1234-<mount id>). The device is mounted using that id.
+func (s *dockerStorage) devicemapperPath(cntr *types.ContainerJSON) (string, error) {
+	name, err := graphData(cntr, "DeviceName")
+	if err != nil {
+		return "", err
+	}
+
+	mountID := name[strings.LastIndex(name, "-")+1:]
+	if mountID == "" {
+		return "", fmt.Errorf("unexpected device name: %s", name)
+	}
+
+	return filepath.Join(s.root, "devicemapper", "mnt", mountID, "rootfs"), nil
+}
+
+// isRootless checks docker's security options for RootlessKit.
+func isRootless(info types.Info) bool {
+	for _, opt := range info.SecurityOptions {
+		if strings.Contains(opt, "name=rootless") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,75 @@
+package radar
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testRoot    = filepath.Join("testdata", "docker", "root")
+	testMountID = "9f3e6a1c0b2d4e5f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f"
+)
+
+func loadInspect(t *testing.T, driver string) *types.ContainerJSON {
+	data, err := ioutil.ReadFile(
+		filepath.Join("testdata", "docker", driver+".json"))
+	require.NoError(t, err)
+
+	var cntr types.ContainerJSON
+	require.NoError(t, json.Unmarshal(data, &cntr))
+
+	return &cntr
+}
+
+func TestDockerRootPath(t *testing.T) {
+	tests := []struct {
+		driver string
+		path   string
+	}{
+		{"overlay2", "/var/lib/docker/overlay2/" + testMountID + "/merged"},
+		{"fuse-overlayfs",
+			"/home/user/.local/share/docker/fuse-overlayfs/" + testMountID + "/merged"},
+		{"btrfs", filepath.Join(testRoot, "btrfs", "subvolumes", testMountID)},
+		{"zfs", "/var/lib/docker/zfs/graph/" + testMountID},
+		{"devicemapper",
+			filepath.Join(testRoot, "devicemapper", "mnt", testMountID, "rootfs")},
+	}
+
+	for _, test := range tests {
+		storage := &dockerStorage{root: testRoot}
+
+		path, err := storage.rootPath(loadInspect(t, test.driver))
+		require.NoError(t, err, test.driver)
+		assert.Equal(t, test.path, path, test.driver)
+	}
+}
+
+func TestDockerRootPathRootless(t *testing.T) {
+	storage := &dockerStorage{root: testRoot, rootless: true}
+
+	_, err := storage.rootPath(loadInspect(t, "overlay2"))
+	assert.Equal(t, errRootless, err)
+}
+
+func TestDockerRootPathUnsupported(t *testing.T) {
+	storage := &dockerStorage{root: testRoot}
+
+	_, err := storage.rootPath(loadInspect(t, "vfs"))
+	assert.EqualError(t, err, "unsupported storage driver: vfs")
+}
+
+func TestDockerRootPathMissingData(t *testing.T) {
+	storage := &dockerStorage{root: testRoot}
+
+	cntr := loadInspect(t, "zfs")
+	delete(cntr.GraphDriver.Data, "Mountpoint")
+
+	_, err := storage.rootPath(cntr)
+	assert.Contains(t, err.Error(), "did not report Mountpoint")
+}
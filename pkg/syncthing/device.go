@@ -1,10 +1,23 @@
 package syncthing
 
 import (
+	"fmt"
+
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/protocol"
 )
 
+// NewDeviceConfiguration returns a device with syncthing's defaults.
+func NewDeviceConfiguration(
+	id protocol.DeviceID, name string) config.DeviceConfiguration {
+
+	device := config.New(id).Defaults.Device.Copy()
+	device.DeviceID = id
+	device.Name = name
+
+	return device
+}
+
 // GetDevice takes a device ID, looks in the current configuration and returns
 // that device.
 func (s *Server) GetDevice(id protocol.DeviceID) *config.DeviceConfiguration {
@@ -18,19 +31,38 @@ func (s *Server) GetDevice(id protocol.DeviceID) *config.DeviceConfiguration {
 }
 
 // SetDevice takes a complete device and adds it to the local configuration.
-// Server.Update() will then save this. Note that if the device already exists
-// it is simply overwritten.
+// Note that if the device already exists it is simply overwritten. Servers
+// that support per-object configuration apply the device immediately,
+// otherwise Server.Update() will then save this.
 func (s *Server) SetDevice(device *config.DeviceConfiguration) error {
-	s.RemoveDevice(device.DeviceID)
+	s.removeDevice(device.DeviceID)
 
 	s.Config.Devices = append(s.Config.Devices, *device)
 
-	return nil
+	if !s.liveConfig {
+		return nil
+	}
+
+	return checkResponse(s.client.NewRequest().
+		SetBody(device).
+		Put(fmt.Sprintf("config/devices/%s", device.DeviceID)))
 }
 
 // RemoveDevice takes a device id and removes it from the local configuration.
-// Server.Update() will then save this.
-func (s *Server) RemoveDevice(id protocol.DeviceID) {
+// Servers that support per-object configuration remove the device
+// immediately, otherwise Server.Update() will then save this.
+func (s *Server) RemoveDevice(id protocol.DeviceID) error {
+	s.removeDevice(id)
+
+	if !s.liveConfig {
+		return nil
+	}
+
+	return checkResponse(s.client.NewRequest().
+		Delete(fmt.Sprintf("config/devices/%s", id)))
+}
+
+func (s *Server) removeDevice(id protocol.DeviceID) {
 	for i, device := range s.Config.Devices {
 		if device.DeviceID == id {
 			s.Config.Devices[i] = s.Config.Devices[len(s.Config.Devices)-1]
@@ -35,8 +35,14 @@ func (s *Server) Events() (<-chan events.Event, error) {
 					SetResult([]events.Event{}).
 					Get("events")
 
+				// Event ids start over when syncthing restarts, begin again from the
+				// start once it's back. Other errors (eg. the long poll timing out)
+				// carry on from the last event.
 				if err != nil {
 					log.Warn(err)
+					if s.eventsRestarted(since) {
+						since = 0
+					}
 					continue
 				}
 
@@ -50,3 +56,24 @@ func (s *Server) Events() (<-chan events.Event, error) {
 
 	return out, nil
 }
+
+// eventsRestarted checks whether the server has been restarted since the
+// event with id since, its latest event would have a lower id. When the server
+// can't be reached, it has not been restarted as far as anyone knows.
+func (s *Server) eventsRestarted(since int) bool {
+	resp, err := s.client.NewRequest().
+		SetQueryParams(map[string]string{
+			"since":   "0",
+			"limit":   "1",
+			"timeout": "0",
+		}).
+		SetResult([]events.Event{}).
+		Get("events")
+	if err != nil || resp.IsError() {
+		return false
+	}
+
+	latest := *resp.Result().(*[]events.Event)
+
+	return len(latest) == 0 || latest[len(latest)-1].SubscriptionID < since
+}
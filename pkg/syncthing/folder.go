@@ -1,9 +1,32 @@
 package syncthing
 
 import (
+	"fmt"
+
 	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
 )
 
+// NewFolderConfiguration returns a folder with syncthing's defaults that is
+// shared with the provided device.
+func NewFolderConfiguration(
+	device protocol.DeviceID,
+	id string,
+	label string,
+	fsType fs.FilesystemType,
+	path string) config.FolderConfiguration {
+
+	folder := config.New(device).Defaults.Folder.Copy()
+	folder.ID = id
+	folder.Label = label
+	folder.FilesystemType = fsType
+	folder.Path = path
+	folder.Devices = []config.FolderDeviceConfiguration{{DeviceID: device}}
+
+	return folder
+}
+
 // GetFolder takes the folder id (not the path) and returns it from the current
 // configuration.
 func (s *Server) GetFolder(id string) *config.FolderConfiguration {
@@ -17,24 +40,43 @@ func (s *Server) GetFolder(id string) *config.FolderConfiguration {
 }
 
 // SetFolder takes a fully configured folder and adds it to the local
-// configuration. Server.Update() will then save this. Note that if the
-// folder already exists, it is simply overwritten.
+// configuration. Note that if the folder already exists, it is simply
+// overwritten. Servers that support per-object configuration apply the folder
+// immediately, otherwise Server.Update() will then save this.
 func (s *Server) SetFolder(folder *config.FolderConfiguration) error {
 	folder.FSWatcherEnabled = true
 	folder.FSWatcherDelayS = 1
 	folder.MaxConflicts = 0
 	folder.CopyOwnershipFromParent = true
 
-	s.RemoveFolder(folder.ID)
+	s.removeFolder(folder.ID)
 
 	s.Config.Folders = append(s.Config.Folders, *folder)
 
-	return nil
+	if !s.liveConfig {
+		return nil
+	}
+
+	return checkResponse(s.client.NewRequest().
+		SetBody(folder).
+		Put(fmt.Sprintf("config/folders/%s", folder.ID)))
 }
 
 // RemoveFolder takes a folder id (not the path) and removes it from the
-// local configuration. Server.Update() will then save this.
-func (s *Server) RemoveFolder(id string) {
+// local configuration. Servers that support per-object configuration remove
+// the folder immediately, otherwise Server.Update() will then save this.
+func (s *Server) RemoveFolder(id string) error {
+	s.removeFolder(id)
+
+	if !s.liveConfig {
+		return nil
+	}
+
+	return checkResponse(s.client.NewRequest().
+		Delete(fmt.Sprintf("config/folders/%s", id)))
+}
+
+func (s *Server) removeFolder(id string) {
 	for i, folder := range s.Config.Folders {
 		if folder.ID == id {
 			s.Config.Folders[i] = s.Config.Folders[len(s.Config.Folders)-1]
@@ -0,0 +1,62 @@
+package syncthing
+
+import (
+	"sync"
+	"time"
+)
+
+// RestartWindow is how long a Restarter waits for more requests before
+// restarting.
+var RestartWindow = 2 * time.Second
+
+// Restarter coalesces restarts of a syncthing server. Every request made within
+// the window is batched together and results in a single restart. All the
+// requests in the batch wait for that restart and get its result. Requests made
+// while a restart is running start a new batch, as whatever they were waiting
+// for might have happened after the restart began.
+type Restarter struct {
+	window time.Duration
+
+	lock    sync.Mutex
+	pending *restartBatch
+}
+
+type restartBatch struct {
+	restart func() error
+	done    chan struct{}
+	err     error
+}
+
+// NewRestarter is the constructor for Restarter.
+func NewRestarter(window time.Duration) *Restarter {
+	return &Restarter{window: window}
+}
+
+// Restart requests a restart and blocks until it has happened. The restart
+// function of the first request in a batch is the one that runs.
+func (r *Restarter) Restart(restart func() error) error {
+	r.lock.Lock()
+	batch := r.pending
+	if batch == nil {
+		batch = &restartBatch{
+			restart: restart,
+			done:    make(chan struct{}),
+		}
+		r.pending = batch
+
+		time.AfterFunc(r.window, func() { r.run(batch) })
+	}
+	r.lock.Unlock()
+
+	<-batch.done
+	return batch.err
+}
+
+func (r *Restarter) run(batch *restartBatch) {
+	r.lock.Lock()
+	r.pending = nil
+	r.lock.Unlock()
+
+	batch.err = batch.restart()
+	close(batch.done)
+}
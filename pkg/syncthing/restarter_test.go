@@ -0,0 +1,36 @@
+package syncthing
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestarterCoalesces(t *testing.T) {
+	restarter := NewRestarter(50 * time.Millisecond)
+
+	var count int32
+	restart := func() error {
+		atomic.AddInt32(&count, 1)
+		return fmt.Errorf("restarted")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.EqualError(t, restarter.Restart(restart), "restarted")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+
+	assert.Error(t, restarter.Restart(restart))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count))
+}
@@ -2,6 +2,7 @@ package syncthing
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"gopkg.in/resty.v1"
@@ -23,6 +24,11 @@ type Server struct {
 
 	client *resty.Client
 	stop   chan bool
+
+	// liveConfig is set when the server supports the per-object configuration
+	// endpoints (syncthing v1.12+). Changes made through them apply without a
+	// restart.
+	liveConfig bool
 }
 
 // NewServer constructs a Server with the provided host and apikey.
@@ -51,6 +57,31 @@ func NewServer(host string, apikey string) (*Server, error) {
 	return server, nil
 }
 
+// Older versions of syncthing do not have the per-object endpoints and return
+// a 404 for them.
+func (s *Server) detectLiveConfig() {
+	resp, err := s.client.NewRequest().Get("config/folders")
+	s.liveConfig = err == nil && resp.StatusCode() == http.StatusOK
+
+	log.WithFields(log.Fields{
+		"url":        s.URL,
+		"liveConfig": s.liveConfig,
+	}).Debug("detected syncthing config api")
+}
+
+func checkResponse(resp *resty.Response, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf(
+			"%s %s: %s", resp.Request.Method, resp.Request.URL, resp.Status())
+	}
+
+	return nil
+}
+
 func (s *Server) String() string {
 	return debug.YamlString(s)
 }
@@ -61,7 +92,8 @@ func (s *Server) Fields() log.Fields {
 }
 
 // Refresh pulls the latest configuration from the configured server and
-// updates Server.Config with that value.
+// updates Server.Config with that value. The server might have been replaced
+// by a different version, so the supported config api is detected again.
 func (s *Server) Refresh() error {
 	resp, err := s.client.NewRequest().
 		SetResult(&config.Configuration{}).
@@ -78,11 +110,19 @@ func (s *Server) Refresh() error {
 	}
 	s.ID = id
 
+	s.detectLiveConfig()
+
 	return nil
 }
 
-// Update takes the current config, sets the server's config to that.
+// Update takes the current config, sets the server's config to that. It is
+// only required for older versions of syncthing, servers with per-object
+// configuration have already applied every change.
 func (s *Server) Update() error {
+	if s.liveConfig {
+		return nil
+	}
+
 	if _, err := s.client.NewRequest().
 		SetBody(s.Config).
 		Post("system/config"); err != nil {
@@ -98,6 +138,36 @@ func (s *Server) Update() error {
 	return nil
 }
 
+// RestartRequired checks whether the server has configuration changes that
+// will only apply after a restart.
+func (s *Server) RestartRequired() (bool, error) {
+	if s.liveConfig {
+		var result struct {
+			RequiresRestart bool `json:"requiresRestart"`
+		}
+
+		if err := checkResponse(s.client.NewRequest().
+			SetResult(&result).
+			Get("config/restart-required")); err != nil {
+			return false, err
+		}
+
+		return result.RequiresRestart, nil
+	}
+
+	var result struct {
+		ConfigInSync bool `json:"configInSync"`
+	}
+
+	if err := checkResponse(s.client.NewRequest().
+		SetResult(&result).
+		Get("system/config/insync")); err != nil {
+		return false, err
+	}
+
+	return !result.ConfigInSync, nil
+}
+
 // Restart rolls the remote server. Because of how syncthing runs, this just
 // happens in the background with only minimal interruption.
 func (s *Server) Restart() error {
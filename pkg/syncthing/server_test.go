@@ -0,0 +1,72 @@
+package syncthing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func testServer(t *testing.T, live bool) (*Server, *[]string) {
+	id := protocol.NewDeviceID([]byte("ksync"))
+	requests := []string{}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			if !live && strings.HasPrefix(r.URL.Path, "/rest/config/") {
+				http.NotFound(w, r)
+				return
+			}
+
+			w.Header().Set("X-Syncthing-Id", id.String())
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, "{}") // nolint: errcheck
+		}))
+	t.Cleanup(ts.Close)
+
+	server, err := NewServer(strings.TrimPrefix(ts.URL, "http://"), "key")
+	require.NoError(t, err)
+
+	requests = requests[:0]
+
+	return server, &requests
+}
+
+func TestSetFolderLive(t *testing.T) {
+	server, requests := testServer(t, true)
+
+	folder := NewFolderConfiguration(
+		server.ID, "test", "test", fs.FilesystemTypeBasic, "/tmp")
+	require.NoError(t, server.SetFolder(&folder))
+	require.NoError(t, server.Update())
+	require.NoError(t, server.RemoveFolder("test"))
+
+	assert.Equal(t, []string{
+		"PUT /rest/config/folders/test",
+		"DELETE /rest/config/folders/test",
+	}, *requests)
+	assert.Empty(t, server.Config.Folders)
+}
+
+func TestSetFolderFallback(t *testing.T) {
+	server, requests := testServer(t, false)
+
+	folder := NewFolderConfiguration(
+		server.ID, "test", "test", fs.FilesystemTypeBasic, "/tmp")
+	require.NoError(t, server.SetFolder(&folder))
+	require.NoError(t, server.Update())
+
+	assert.Equal(t, []string{
+		"POST /rest/system/config",
+		"POST /rest/system/status",
+	}, *requests)
+	assert.Len(t, server.Config.Folders, 1)
+}